@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gosukretess/battleships/proto/gamepb"
+	"github.com/gosukretess/battleships/proto/userpb"
+	"google.golang.org/grpc/status"
+)
+
+var presenceNames = map[userpb.PresenceStatus]string{
+	userpb.PresenceStatus_OFFLINE:  "offline",
+	userpb.PresenceStatus_IDLE:     "online",
+	userpb.PresenceStatus_IN_QUEUE: "szuka gry",
+	userpb.PresenceStatus_IN_GAME:  "w grze",
+}
+
+// friendList is the friends screen. Presence updates arrive in the
+// background while the player types commands.
+type friendList struct {
+	mu       sync.Mutex
+	friends  []*userpb.Friend
+	presence map[string]userpb.PresenceStatus
+}
+
+func (l *friendList) set(friends []*userpb.Friend) {
+	l.mu.Lock()
+	l.friends = friends
+	for _, f := range friends {
+		if _, ok := l.presence[f.GetUser().GetId()]; !ok {
+			l.presence[f.GetUser().GetId()] = f.GetPresence()
+		}
+	}
+	l.mu.Unlock()
+	l.draw()
+}
+
+func (l *friendList) update(u *userpb.PresenceUpdate) {
+	l.mu.Lock()
+	l.presence[u.GetUserId()] = u.GetStatus()
+	l.mu.Unlock()
+	l.draw()
+}
+
+// get returns the friend with a number shown on the screen.
+func (l *friendList) get(number string) (*userpb.Friend, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 || n > len(l.friends) {
+		return nil, false
+	}
+	return l.friends[n-1], true
+}
+
+func (l *friendList) draw() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var friends, requests strings.Builder
+	friends.WriteString("Znajomi\n\n")
+	requests.WriteString("Zaproszenia\n\n")
+	for i, f := range l.friends {
+		switch f.GetStatus() {
+		case userpb.FriendStatus_FRIEND:
+			fmt.Fprintf(&friends, "%3d  %-24.24s %s\n", i+1, f.GetUser().GetName(), presenceNames[l.presence[f.GetUser().GetId()]])
+		case userpb.FriendStatus_INCOMING:
+			fmt.Fprintf(&requests, "%3d  %-24.24s od %s\n", i+1, f.GetUser().GetName(), f.GetUser().GetEmail())
+		case userpb.FriendStatus_OUTGOING:
+			fmt.Fprintf(&requests, "%3d  %-24.24s wysłane\n", i+1, f.GetUser().GetName())
+		}
+	}
+	setText(firstTable, friends.String())
+	setText(secondTable, requests.String())
+}
+
+// showFriends lists the friends of the user with their presence and lets
+// them manage friends and challenge one to a game.
+func showFriends(userClient *userpb.UserServiceClient, gameClient *gamepb.GameServiceClient, userId string) {
+	defer func() {
+		setText(firstTable, "")
+		setText(secondTable, "")
+	}()
+
+	list := &friendList{presence: make(map[string]userpb.PresenceStatus)}
+	lookingForGame := false
+	stop := watchPresence(userClient, list, lookingForGame)
+	defer func() { stop() }()
+
+	for {
+		setHeader("ZNAJOMI")
+		resp, err := (*userClient).ListFriends(context.Background(), &userpb.ListFriendsRequest{})
+		if err != nil {
+			writeLog(fmt.Sprintf("Nie udało się pobrać znajomych: %s", status.Convert(err).Message()))
+			return
+		}
+		list.set(resp.GetFriends())
+		writeLog("+email – zaproś, a N – przyjmij, u N – usuń, w N – wyzwij na grę, k N – gra korespondencyjna, s – szukam gry (wł./wył.), Q – powrót")
+
+		input := readInput()
+		command, arg, _ := strings.Cut(input, " ")
+		switch {
+		case strings.EqualFold(input, "q"):
+			return
+		case strings.EqualFold(input, "s"):
+			stop()
+			lookingForGame = !lookingForGame
+			stop = watchPresence(userClient, list, lookingForGame)
+			if lookingForGame {
+				writeLog("Znajomi widzą, że szukasz gry.")
+			}
+		case strings.HasPrefix(input, "+"):
+			inviteFriend(userClient, strings.TrimSpace(input[1:]))
+		case command == "a" || command == "u" || command == "w" || command == "k":
+			friend, ok := list.get(arg)
+			if !ok {
+				writeLog("Nie ma znajomego o tym numerze.")
+				continue
+			}
+			friendCommand(userClient, gameClient, userId, command, friend)
+		}
+	}
+}
+
+// watchPresence feeds presence updates of the friends into list until the
+// returned function is called.
+func watchPresence(client *userpb.UserServiceClient, list *friendList, lookingForGame bool) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := (*client).WatchPresence(ctx, &userpb.WatchPresenceRequest{LookingForGame: lookingForGame})
+	if err != nil {
+		writeLog(fmt.Sprintf("Nie udało się obserwować znajomych: %s", status.Convert(err).Message()))
+		return cancel
+	}
+	go func() {
+		for {
+			update, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			list.update(update)
+		}
+	}()
+	return cancel
+}
+
+func inviteFriend(client *userpb.UserServiceClient, email string) {
+	found, err := (*client).FindUserByEmail(context.Background(), &userpb.FindUserByEmailRequest{Email: email})
+	if err != nil {
+		writeLog(fmt.Sprintf("Nie znaleziono gracza: %s", status.Convert(err).Message()))
+		return
+	}
+	resp, err := (*client).RequestFriend(context.Background(), &userpb.RequestFriendRequest{UserId: found.GetUser().GetId()})
+	if err != nil {
+		writeLog(fmt.Sprintf("Nie udało się wysłać zaproszenia: %s", status.Convert(err).Message()))
+		return
+	}
+	if resp.GetFriend().GetStatus() == userpb.FriendStatus_FRIEND {
+		writeLog(fmt.Sprintf("%s też Cię zaprosił(a), jesteście znajomymi.", found.GetUser().GetName()))
+	} else {
+		writeLog(fmt.Sprintf("Wysłano zaproszenie do %s.", found.GetUser().GetName()))
+	}
+}
+
+func friendCommand(userClient *userpb.UserServiceClient, gameClient *gamepb.GameServiceClient, userId, command string, friend *userpb.Friend) {
+	ctx := context.Background()
+	friendId := friend.GetUser().GetId()
+	var err error
+	switch command {
+	case "a":
+		_, err = (*userClient).AcceptFriend(ctx, &userpb.AcceptFriendRequest{UserId: friendId})
+	case "u":
+		_, err = (*userClient).RemoveFriend(ctx, &userpb.RemoveFriendRequest{UserId: friendId})
+	case "w", "k":
+		if friend.GetStatus() != userpb.FriendStatus_FRIEND {
+			writeLog("Wyzwać można tylko znajomych.")
+			return
+		}
+		mode := gamepb.GameMode_REALTIME
+		if command == "k" {
+			mode = gamepb.GameMode_CORRESPONDENCE
+		}
+		_, err = (*gameClient).CreateGame(ctx, &gamepb.CreateGameRequest{UserId1: userId, UserId2: friendId, Mode: mode})
+		if err == nil {
+			writeLog(fmt.Sprintf("Utworzono grę z %s. Wróć do menu i wybierz [1] Graj.", friend.GetUser().GetName()))
+		}
+	}
+	if err != nil {
+		writeLog(fmt.Sprintf("Nie udało się: %s", status.Convert(err).Message()))
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gosukretess/battleships/proto/gamepb"
+	"github.com/gosukretess/battleships/proto/userpb"
+	"google.golang.org/grpc/status"
+)
+
+// selectGame lists the games of the user, active ones on the left board and
+// finished ones on the right, and returns the game in progress the player
+// picks. Picking a finished game shows how it ended. It returns nil when the
+// player goes back to the menu.
+func selectGame(userClient *userpb.UserServiceClient, gameClient *gamepb.GameServiceClient, userId string) *gamepb.Game {
+	defer func() {
+		setText(firstTable, "")
+		setText(secondTable, "")
+	}()
+
+	names := userNames(userClient)
+	pageToken := ""
+	for {
+		setHeader("TWOJE GRY")
+		resp, err := (*gameClient).ListGames(context.Background(), &gamepb.ListGamesRequest{
+			UserId:    userId,
+			OrderBy:   "created desc",
+			PageSize:  20,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			writeLog(fmt.Sprintf("Nie udało się pobrać gier: %s", status.Convert(err).Message()))
+			return nil
+		}
+
+		games := resp.GetGames()
+		var active, finished strings.Builder
+		active.WriteString("Gry w toku\n\n")
+		finished.WriteString("Zakończone gry\n\n")
+		for i, g := range games {
+			opponent := g.GetUserId1()
+			if opponent == userId {
+				opponent = g.GetUserId2()
+			}
+			line := fmt.Sprintf("%3d  %-18.18s %-8s %s", i+1, names[opponent], g.GetRules().GetName(),
+				g.GetCreated().AsTime().Local().Format("2006-01-02 15:04"))
+			if g.GetStatus() == gamepb.GameStatus_IN_PROGRESS {
+				if g.GetMode() == gamepb.GameMode_CORRESPONDENCE {
+					line += "  korespondencyjna"
+				}
+				if g.GetNextUser() == userId {
+					line += "  twój ruch"
+					if deadline := g.GetTurnDeadline(); deadline != nil {
+						line += " do " + deadline.AsTime().Local().Format("2006-01-02 15:04")
+					}
+				}
+				fmt.Fprintln(&active, line)
+			} else {
+				fmt.Fprintf(&finished, "%s  %s\n", line, gameResult(g, userId))
+			}
+		}
+		if len(games) == 0 {
+			active.WriteString("Nie masz jeszcze żadnych gier.\nWyzwij znajomego w menu [3] Znajomi.")
+		}
+		setText(firstTable, active.String())
+		setText(secondTable, finished.String())
+
+		help := "Numer gry – wybierz, Q – powrót"
+		if resp.GetNextPageToken() != "" {
+			help = "Enter – następna strona, " + help
+		} else if pageToken != "" {
+			help = "Enter – pierwsza strona, " + help
+		}
+		writeLog(help)
+
+		input := readInput()
+		if strings.EqualFold(input, "q") {
+			return nil
+		}
+		if input == "" {
+			pageToken = resp.GetNextPageToken()
+			continue
+		}
+		n, err := strconv.Atoi(input)
+		if err != nil || n < 1 || n > len(games) {
+			writeLog("Nie ma gry o tym numerze.")
+			continue
+		}
+		if game := games[n-1]; game.GetStatus() == gamepb.GameStatus_IN_PROGRESS {
+			return game
+		}
+		showFinishedGame(gameClient, games[n-1], userId)
+	}
+}
+
+// showFinishedGame draws the final boards of a game until the player
+// presses Enter.
+func showFinishedGame(client *gamepb.GameServiceClient, game *gamepb.Game, userId string) {
+	opponent := game.GetUserId1()
+	if opponent == userId {
+		opponent = game.GetUserId2()
+	}
+	boardSize := game.GetRules().GetBoardSize()
+	if boardSize == 0 {
+		boardSize = 8
+	}
+
+	ctx := context.Background()
+	ships, err := (*client).GetShips(ctx, &gamepb.GetShipsRequest{GameId: game.GetId(), UserId: userId})
+	if err != nil {
+		writeLog(fmt.Sprintf("Nie udało się pobrać gry: %s", status.Convert(err).Message()))
+		return
+	}
+	moves, _ := (*client).GetMoves(ctx, &gamepb.GetMovesRequest{GameId: game.GetId(), UserId: userId})
+	enemyMoves, _ := (*client).GetMoves(ctx, &gamepb.GetMovesRequest{GameId: game.GetId(), UserId: opponent})
+
+	setHeader(strings.ToUpper(gameResult(game, userId)))
+	drawEnemyTable(moves, boardSize, app, firstTable)
+	drawUserTable(ships, enemyMoves, boardSize, app, secondTable)
+	writeLog("Enter – powrót do listy gier")
+	readInput()
+}
+
+func gameResult(game *gamepb.Game, userId string) string {
+	result := "przegrana"
+	if game.GetWinner() == userId {
+		result = "wygrana"
+	}
+	if game.GetStatus() == gamepb.GameStatus_RESIGNED {
+		result += " przez poddanie"
+	}
+	return result
+}
+
+// userNames maps user ids to names, to show who the opponents are.
+func userNames(client *userpb.UserServiceClient) map[string]string {
+	names := make(map[string]string)
+	resp, err := (*client).GetUsers(context.Background(), &userpb.GetUsersRequest{})
+	if err != nil {
+		return names
+	}
+	for _, u := range resp.GetUsers() {
+		names[u.GetId()] = u.GetName()
+	}
+	return names
+}
@@ -2,18 +2,27 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
-	"strconv"
+	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/gosukretess/battleships/internal/auth"
+	"github.com/gosukretess/battleships/internal/config"
+	"github.com/gosukretess/battleships/internal/logging"
+	"github.com/gosukretess/battleships/internal/tracing"
 	"github.com/gosukretess/battleships/proto/gamepb"
 	"github.com/gosukretess/battleships/proto/userpb"
 	"github.com/rivo/tview"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 var app *tview.Application
@@ -24,10 +33,36 @@ var footer *tview.TextView
 var inputField *tview.InputField
 
 func main() {
+	printConfig := flag.Bool("print-config", false, "print the effective configuration and exit")
+	cfg, err := config.LoadClient(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	if *printConfig {
+		if err := config.Print(os.Stdout, cfg); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := logging.Setup(cfg.Log); err != nil {
+		log.Fatal(err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, "battleships-client")
+	if err != nil {
+		log.Fatalf("could not set up tracing: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownTracing(ctx)
+	}()
+
 	app = tview.NewApplication()
 	grid := initUi()
 
-	conn, err := grpc.NewClient("localhost:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	creds := &sessionCredentials{}
+	conn, err := dial(cfg, creds)
 	if err != nil {
 		log.Fatalf("could not connect to gRPC server: %v", err)
 	}
@@ -36,7 +71,7 @@ func main() {
 	userClient := userpb.NewUserServiceClient(conn)
 	gameClient := gamepb.NewGameServiceClient(conn)
 
-	go gameLoop(&userClient, &gameClient)
+	go gameLoop(&userClient, &gameClient, creds)
 
 	if err := app.SetRoot(grid, true).SetFocus(inputField).Run(); err != nil {
 		panic(err)
@@ -44,6 +79,64 @@ func main() {
 
 }
 
+func dial(cfg *config.Client, session *sessionCredentials) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if cfg.TLS.IsEnabled() {
+		tlsConfig, err := cfg.TLS.TLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	return grpc.NewClient(cfg.Server,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithPerRPCCredentials(session),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(timeoutInterceptor(cfg.Timeouts.Request), logging.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(logging.StreamClientInterceptor()),
+	)
+}
+
+// sessionCredentials sends the session token from Login with every call.
+type sessionCredentials struct {
+	mu    sync.RWMutex
+	token string
+}
+
+func (c *sessionCredentials) setToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+}
+
+func (c *sessionCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.token == "" {
+		return nil, nil
+	}
+	return map[string]string{auth.Header: "Bearer " + c.token}, nil
+}
+
+// RequireTransportSecurity is false so that the client still works against a
+// local server without TLS.
+func (c *sessionCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// timeoutInterceptor puts a deadline on every unary call that does not have one yet.
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
 func initUi() *tview.Grid {
 	firstTable = tview.NewTextView()
 	secondTable = tview.NewTextView()
@@ -69,165 +162,6 @@ func initUi() *tview.Grid {
 	return grid
 }
 
-func gameLoop(userClient *userpb.UserServiceClient, gameClient *gamepb.GameServiceClient) {
-	var wg sync.WaitGroup
-
-	currentUserId := getUserId(userClient)
-	gameId, enemyId, nextUserId := getGameId(gameClient, currentUserId)
-	userTurn := false
-	if nextUserId == currentUserId {
-		userTurn = true
-	}
-
-	footer.Clear()
-
-	stream, err := (*gameClient).PlayerMove(context.Background())
-	if err != nil {
-		log.Fatalf("Cannot connect to start stream: %v", err)
-	}
-
-	userShips, _ := (*gameClient).GetShips(context.Background(), &gamepb.GetShipsRequest{
-		GameId: gameId,
-		UserId: currentUserId,
-	})
-
-	moves, _ := (*gameClient).GetMoves(context.Background(), &gamepb.GetMovesRequest{
-		GameId: gameId,
-		UserId: currentUserId,
-	})
-
-	enemyMoves, _ := (*gameClient).GetMoves(context.Background(), &gamepb.GetMovesRequest{
-		GameId: gameId,
-		UserId: enemyId,
-	})
-
-	drawEnemyTable(moves, app, firstTable)
-	drawUserTable(userShips, enemyMoves, app, secondTable)
-
-	isUserTurn := make(chan bool, 1)
-	isUserTurn <- userTurn
-
-	// RECEIVE EVENT
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for {
-			event, err := stream.Recv()
-			if err != nil {
-				log.Printf("Stream ended or error: %v", err)
-				break
-			}
-
-			moves, _ := (*gameClient).GetMoves(context.Background(), &gamepb.GetMovesRequest{
-				GameId: gameId,
-				UserId: currentUserId,
-			})
-
-			enemyMoves, _ := (*gameClient).GetMoves(context.Background(), &gamepb.GetMovesRequest{
-				GameId: gameId,
-				UserId: enemyId,
-			})
-
-			drawEnemyTable(moves, app, firstTable)
-			drawUserTable(userShips, enemyMoves, app, secondTable)
-
-			if event.UserId2 == currentUserId {
-				hit := "TRAFIENIE"
-				if event.Type == gamepb.EventType_MISS {
-					hit = "PUDŁO"
-				}
-				writeLog(fmt.Sprintf("Przeciwnik strzelił w (%s,%d) - %s", toLetter(event.X+1), event.Y+1, hit))
-				writeLog("Twój ruch! Podaj współrzędne (np. B4)...")
-				app.SetFocus(inputField)
-				isUserTurn <- true
-			} else {
-				if event.Type == gamepb.EventType_TAKEN {
-					writeLog(fmt.Sprintf("Powtórzony strzał w (%s,%d). Podaj inne współrzędne.", toLetter(event.X+1), event.Y+1))
-					isUserTurn <- true
-				} else {
-					hit := "TRAFIENIE"
-					if event.Type == gamepb.EventType_MISS {
-						hit = "PUDŁO"
-					}
-					writeLog(fmt.Sprintf("Strzeliłeś w (%s,%d) - %s", toLetter(event.X+1), event.Y+1, hit))
-					writeLog("Czekaj na ruch przeciwnika...")
-					app.SetFocus(nil)
-					isUserTurn <- false
-				}
-			}
-		}
-	}()
-
-	moveChan := make(chan string)
-	inputField.SetDoneFunc(func(key tcell.Key) {
-		if key != tcell.KeyEnter {
-			return
-		}
-		text := strings.TrimSpace(inputField.GetText())
-		inputField.SetText("")
-		moveChan <- text
-	})
-
-	// SEND MOVE
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-
-		for turn := range isUserTurn {
-			if turn {
-				for {
-					setHeader("TWOJA TURA")
-					app.QueueUpdateDraw(func() {
-						header.SetTextColor(tcell.Color40)
-					})
-
-					input := <-moveChan
-					input = strings.TrimSpace(strings.ToUpper(input))
-
-					if len(input) < 2 {
-						writeLog("Nieprawidłowy format. Spróbuj ponownie.")
-						continue
-					}
-
-					colLetter := input[0]
-					rowStr := input[1:]
-					x := int32(colLetter - 'A')
-					yInt, err := strconv.Atoi(rowStr)
-					if err != nil || x < 0 || x > 7 || yInt < 1 || yInt > 8 {
-						writeLog("Nieprawidłowe współrzędne. Dozwolone A1–H8.")
-						continue
-					}
-					y := int32(yInt - 1)
-
-					event := &gamepb.GameEvent{
-						GameId:  gameId,
-						UserId1: currentUserId,
-						UserId2: enemyId,
-						X:       x,
-						Y:       y,
-						Type:    gamepb.EventType_MOVE,
-					}
-
-					if err := stream.Send(event); err != nil {
-						log.Fatalf("Błąd przy wysyłaniu eventu: %v", err)
-						continue
-					}
-
-					break
-				}
-
-			} else {
-				setHeader("TURA PRZECIWNIKA")
-				app.QueueUpdateDraw(func() {
-					header.SetTextColor(tcell.Color196)
-				})
-			}
-		}
-	}()
-
-	wg.Wait()
-}
-
 func setHeader(text string) {
 	var s strings.Builder
 	s.WriteString("\n")
@@ -250,69 +184,177 @@ func writeLog(text string) {
 }
 
 func toLetter(n int32) string {
-	if n < 1 || n > 8 {
+	if n < 1 || n > 26 {
 		return ""
 	}
 	return string('A' + rune(n-1))
 }
 
-func getUserId(client *userpb.UserServiceClient) string {
-	resp, err := (*client).GetUsers(context.Background(), &userpb.GetUsersRequest{})
-	if err != nil {
-		log.Fatalf("Could not get users: %v", err)
-	}
+// login asks for an email and password until the server accepts them and
+// returns the id of the logged in user.
+func login(client *userpb.UserServiceClient, creds *sessionCredentials) string {
+	setHeader("Zaloguj się")
+	for {
+		writeLog("Podaj email:")
+		email := readInput()
 
-	setHeader("Wybierz użytkownika")
-	users := make(map[int]string)
-	index := 1
-	for _, user := range resp.Users {
-		users[index] = user.Id
+		writeLog("Podaj hasło:")
 		app.QueueUpdateDraw(func() {
-			fmt.Fprintf(footer, "[%d] %s, Email: %s\n", index, user.Name, user.Email)
+			inputField.SetMaskCharacter('*')
+		})
+		password := readInput()
+		app.QueueUpdateDraw(func() {
+			inputField.SetMaskCharacter(0)
 		})
-		index += 1
-	}
 
-	userChan := make(chan string)
+		resp, err := (*client).Login(context.Background(), &userpb.LoginRequest{Email: email, Password: password})
+		if err != nil {
+			writeLog(fmt.Sprintf("Logowanie nieudane: %s", status.Convert(err).Message()))
+			continue
+		}
+		creds.setToken(resp.GetSession().GetToken())
+		writeLog(fmt.Sprintf("Zalogowano jako %s.", resp.GetSession().GetUser().GetName()))
+		return resp.GetSession().GetUser().GetId()
+	}
+}
 
-	inputField.SetDoneFunc(func(key tcell.Key) {
-		if key != tcell.KeyEnter {
+// mainMenu lets the player look at the leaderboard and their friends before
+// the game starts.
+func mainMenu(userClient *userpb.UserServiceClient, gameClient *gamepb.GameServiceClient, userId string) {
+	if inbox, err := (*gameClient).GetInbox(context.Background(), &gamepb.GetInboxRequest{PageSize: 100}); err == nil && len(inbox.GetGames()) > 0 {
+		writeLog(fmt.Sprintf("Gry czekające na Twój ruch: %d", len(inbox.GetGames())))
+	}
+	for {
+		setHeader("MENU")
+		writeLog("[1] Graj   [2] Ranking   [3] Znajomi")
+		switch readInput() {
+		case "1":
 			return
+		case "2":
+			showLeaderboard(gameClient, userId)
+		case "3":
+			showFriends(userClient, gameClient, userId)
 		}
+	}
+}
 
-		text := strings.TrimSpace(inputField.GetText())
-		inputField.SetText("")
+// showLeaderboard draws the leaderboard on the left board and the rating
+// history of the user on the right one until the player goes back.
+func showLeaderboard(client *gamepb.GameServiceClient, userId string) {
+	defer func() {
+		setText(firstTable, "")
+		setText(secondTable, "")
+	}()
 
-		num, err := strconv.Atoi(text)
+	rules, pageToken := "", ""
+	for {
+		setHeader("RANKING " + strings.ToUpper(rules))
+		resp, err := (*client).GetLeaderboard(context.Background(), &gamepb.GetLeaderboardRequest{
+			Rules:     rules,
+			PageSize:  20,
+			PageToken: pageToken,
+		})
 		if err != nil {
-			//setHeader("Niepoprawny numer. Spróbuj ponownie.")
-			return
+			writeLog(fmt.Sprintf("Nie udało się pobrać rankingu: %s", status.Convert(err).Message()))
+			rules, pageToken = "", ""
+			continue
 		}
 
-		if val, ok := users[num]; ok {
-			inputField.SetDoneFunc(nil)
-			userChan <- val
-		} else {
-			//setHeader("Nie ma użytkownika o takim numerze.")
+		var board strings.Builder
+		fmt.Fprintf(&board, "%4s  %-20s %-8s %6s %5s\n", "#", "Gracz", "Zasady", "Elo", "Gry")
+		for _, e := range resp.GetEntries() {
+			name := e.GetName()
+			if e.GetUserId() == userId {
+				name = "> " + name
+			}
+			fmt.Fprintf(&board, "%4d  %-20.20s %-8s %6d %5d\n", e.GetRank(), name, e.GetRules(), e.GetRating(), e.GetGames())
 		}
-	})
+		if len(resp.GetEntries()) == 0 {
+			board.WriteString("\nBrak rozegranych gier.")
+		}
+		setText(firstTable, board.String())
+		setText(secondTable, ratingHistory(client, userId, rules))
+
+		next := "Q – powrót"
+		if resp.GetNextPageToken() != "" {
+			next = "Enter – następna strona, " + next
+		}
+		writeLog(next + ", nazwa zasad (np. draft, classic) – filtr, * – wszystkie zasady")
 
-	return <-userChan
+		switch input := readInput(); strings.ToLower(input) {
+		case "q":
+			return
+		case "":
+			pageToken = resp.GetNextPageToken()
+		case "*":
+			rules, pageToken = "", ""
+		default:
+			rules, pageToken = strings.ToLower(input), ""
+		}
+	}
 }
 
-func getGameId(client *gamepb.GameServiceClient, currentUserId string) (gameId, enemyId, nextUserId string) {
-	resp, err := (*client).GetAllGames(context.Background(), &gamepb.GetAllGamesRequest{})
+func ratingHistory(client *gamepb.GameServiceClient, userId, rules string) string {
+	resp, err := (*client).GetRatingHistory(context.Background(), &gamepb.GetRatingHistoryRequest{UserId: userId, Rules: rules})
 	if err != nil {
-		log.Fatalf("Could not get users: %v", err)
+		return fmt.Sprintf("Nie udało się pobrać historii: %s", status.Convert(err).Message())
 	}
 
-	for _, game := range resp.Games {
-		if game.UserId1 == currentUserId {
-			return game.Id, game.UserId2, game.NextUser
-		} else if game.UserId2 == currentUserId {
-			return game.Id, game.UserId1, game.NextUser
+	var history strings.Builder
+	history.WriteString("Twoja historia rankingu\n\n")
+	changes := resp.GetChanges()
+	// The newest games fit on the screen.
+	if len(changes) > 20 {
+		changes = changes[len(changes)-20:]
+	}
+	for _, c := range changes {
+		result := "P"
+		if c.GetWon() {
+			result = "W"
 		}
+		fmt.Fprintf(&history, "%s  %-8s %s  %5d -> %5d (%+d)\n", c.GetCreated().AsTime().Local().Format("2006-01-02 15:04"),
+			c.GetRules(), result, c.GetRatingBefore(), c.GetRatingAfter(), c.GetRatingAfter()-c.GetRatingBefore())
+	}
+	if len(changes) == 0 {
+		history.WriteString("Brak zmian.")
 	}
+	return history.String()
+}
 
-	return
+// showStats writes the statistics of the user to the log.
+func showStats(client *userpb.UserServiceClient, userId string) {
+	resp, err := (*client).GetUserStats(context.Background(), &userpb.GetUserStatsRequest{UserId: userId})
+	if err != nil {
+		writeLog(fmt.Sprintf("Nie udało się pobrać statystyk: %s", status.Convert(err).Message()))
+		return
+	}
+	stats := resp.GetStats()
+	writeLog(fmt.Sprintf("Gry: %d, wygrane: %d, przegrane: %d (w tym poddane: %d), najdłuższa seria zwycięstw: %d",
+		stats.GetGamesPlayed(), stats.GetWon(), stats.GetLost(), stats.GetResigned(), stats.GetLongestWinStreak()))
+	writeLog(fmt.Sprintf("Celność: %.0f%% (%d/%d), średnio strzałów do wygranej: %.1f",
+		stats.GetAccuracy()*100, stats.GetHits(), stats.GetShots(), stats.GetAverageShotsToWin()))
+	if len(resp.GetAchievements()) > 0 {
+		names := make([]string, 0, len(resp.GetAchievements()))
+		for _, a := range resp.GetAchievements() {
+			names = append(names, a.GetName())
+		}
+		writeLog("Osiągnięcia: " + strings.Join(names, ", "))
+	}
+}
+
+// readInput waits for the next line entered in the input field.
+func readInput() string {
+	lines := make(chan string, 1)
+	app.QueueUpdate(func() {
+		inputField.SetDoneFunc(func(key tcell.Key) {
+			if key != tcell.KeyEnter {
+				return
+			}
+			text := strings.TrimSpace(inputField.GetText())
+			inputField.SetText("")
+			inputField.SetDoneFunc(nil)
+			lines <- text
+		})
+	})
+	return <-lines
 }
@@ -0,0 +1,413 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/gosukretess/battleships/internal/tracing"
+	"github.com/gosukretess/battleships/proto/gamepb"
+	"github.com/gosukretess/battleships/proto/userpb"
+	"google.golang.org/grpc/status"
+)
+
+// activeGame is a game in progress the player is connected to.
+type activeGame struct {
+	id        string
+	enemyId   string
+	enemy     string
+	boardSize int32
+	ships     *gamepb.GetShipsResponse
+	yourTurn  bool
+	// unread is set when the opponent moved while another game was shown.
+	unread bool
+}
+
+// session holds the games in progress of the player, all of them played over
+// one PlayerMove stream. One game at a time is shown on the boards, the header
+// lists all of them with their turn.
+type session struct {
+	mu      sync.Mutex
+	userId  string
+	games   []*activeGame
+	current int
+	client  *gamepb.GameServiceClient
+	stream  gamepb.GameService_PlayerMoveClient
+}
+
+func newActiveGame(client *gamepb.GameServiceClient, game *gamepb.Game, userId string, names map[string]string) *activeGame {
+	g := &activeGame{id: game.GetId(), enemyId: game.GetUserId1(), boardSize: game.GetRules().GetBoardSize()}
+	if g.enemyId == userId {
+		g.enemyId = game.GetUserId2()
+	}
+	g.enemy = names[g.enemyId]
+	if g.enemy == "" {
+		g.enemy = g.enemyId
+	}
+	if g.boardSize == 0 {
+		g.boardSize = 8
+	}
+	g.yourTurn = game.GetNextUser() == userId
+	g.ships, _ = (*client).GetShips(context.Background(), &gamepb.GetShipsRequest{GameId: g.id, UserId: userId})
+	return g
+}
+
+// activeGames returns the games of the user in progress with first, the one
+// they picked, at the front.
+func activeGames(userClient *userpb.UserServiceClient, gameClient *gamepb.GameServiceClient, userId string, first *gamepb.Game) []*activeGame {
+	names := userNames(userClient)
+	games := []*activeGame{newActiveGame(gameClient, first, userId, names)}
+
+	resp, err := (*gameClient).ListGames(context.Background(), &gamepb.ListGamesRequest{
+		UserId:   userId,
+		Status:   gamepb.GameStatus_IN_PROGRESS,
+		OrderBy:  "created",
+		PageSize: 100,
+	})
+	if err != nil {
+		writeLog(fmt.Sprintf("Nie udało się pobrać pozostałych gier: %s", status.Convert(err).Message()))
+		return games
+	}
+	for _, game := range resp.GetGames() {
+		if game.GetId() != first.GetId() {
+			games = append(games, newActiveGame(gameClient, game, userId, names))
+		}
+	}
+	return games
+}
+
+func gameLoop(userClient *userpb.UserServiceClient, gameClient *gamepb.GameServiceClient, creds *sessionCredentials) {
+	var wg sync.WaitGroup
+
+	currentUserId := login(userClient, creds)
+	var game *gamepb.Game
+	for game == nil {
+		mainMenu(userClient, gameClient, currentUserId)
+		game = selectGame(userClient, gameClient, currentUserId)
+	}
+
+	footer.Clear()
+	showStats(userClient, currentUserId)
+
+	stream, err := (*gameClient).PlayerMove(context.Background())
+	if err != nil {
+		log.Fatalf("Cannot connect to start stream: %v", err)
+	}
+	s := &session{
+		userId: currentUserId,
+		games:  activeGames(userClient, gameClient, currentUserId, game),
+		client: gameClient,
+		stream: stream,
+	}
+	for _, g := range s.games {
+		if err := stream.Send(&gamepb.GameEvent{GameId: g.id, UserId1: currentUserId, Type: gamepb.EventType_SUBSCRIBE}); err != nil {
+			log.Fatalf("Cannot subscribe to game: %v", err)
+		}
+	}
+	if len(s.games) > 1 {
+		writeLog(fmt.Sprintf("Masz %d gry w toku. Wpisz :N, aby przełączyć się na grę N.", len(s.games)))
+	}
+	s.show(0)
+
+	// RECEIVE EVENT
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				log.Printf("Stream ended or error: %v", err)
+				return
+			}
+
+			switch event.Type {
+			case gamepb.EventType_SERVER_SHUTDOWN:
+				writeLog("Serwer został wyłączony. Gry zostaną zapisane, uruchom klienta ponownie później.")
+				setHeader("SERWER WYŁĄCZONY")
+				app.QueueUpdateDraw(func() {
+					header.SetTextColor(tcell.Color196)
+					app.SetFocus(nil)
+				})
+				return
+			case gamepb.EventType_ACHIEVEMENT:
+				writeLog(fmt.Sprintf("Nowe osiągnięcie: %s - %s", event.GetAchievement().GetName(), event.GetAchievement().GetDescription()))
+			case gamepb.EventType_GAME_OVER:
+				if s.gameOver(event) {
+					showStats(userClient, currentUserId)
+					return
+				}
+			case gamepb.EventType_HIT, gamepb.EventType_MISS, gamepb.EventType_TAKEN:
+				s.handleShot(event)
+			case gamepb.EventType_ERROR:
+				s.handleError(event)
+			}
+		}
+	}()
+
+	inputField.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+		text := strings.TrimSpace(inputField.GetText())
+		inputField.SetText("")
+		go s.handleInput(text)
+	})
+	app.QueueUpdateDraw(func() {
+		app.SetFocus(inputField)
+	})
+
+	wg.Wait()
+}
+
+// handleInput switches games on ":N" and fires at coordinates otherwise.
+func (s *session) handleInput(input string) {
+	input = strings.ToUpper(input)
+	if number, ok := strings.CutPrefix(input, ":"); ok {
+		n, err := strconv.Atoi(number)
+		s.mu.Lock()
+		valid := err == nil && n >= 1 && n <= len(s.games)
+		s.mu.Unlock()
+		if !valid {
+			writeLog("Nie ma gry o tym numerze.")
+			return
+		}
+		s.show(n - 1)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.games) == 0 {
+		return
+	}
+	g := s.games[s.current]
+	if !g.yourTurn {
+		writeLog("Teraz ruch przeciwnika. Poczekaj albo przełącz się na inną grę (:N).")
+		return
+	}
+
+	if len(input) < 2 {
+		writeLog("Nieprawidłowy format. Spróbuj ponownie.")
+		return
+	}
+	x := int32(input[0] - 'A')
+	yInt, err := strconv.Atoi(input[1:])
+	if err != nil || x < 0 || x >= g.boardSize || yInt < 1 || yInt > int(g.boardSize) {
+		writeLog(fmt.Sprintf("Nieprawidłowe współrzędne. Dozwolone A1–%s%d.", toLetter(g.boardSize), g.boardSize))
+		return
+	}
+
+	event := &gamepb.GameEvent{
+		GameId:  g.id,
+		UserId1: s.userId,
+		UserId2: g.enemyId,
+		X:       x,
+		Y:       int32(yInt - 1),
+		Type:    gamepb.EventType_MOVE,
+	}
+	if err := s.stream.Send(event); err != nil {
+		log.Fatalf("Błąd przy wysyłaniu eventu: %v", err)
+	}
+	// Wait for the result before the next shot.
+	g.yourTurn = false
+}
+
+// handleShot updates the turn of the game a shot was fired in, and its
+// boards if it is the one shown.
+func (s *session) handleShot(event *gamepb.GameEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(event.GameId)
+	if i < 0 {
+		return
+	}
+	g := s.games[i]
+	shown := i == s.current
+	prefix := ""
+	if !shown {
+		prefix = fmt.Sprintf("[%d: %s] ", i+1, g.enemy)
+	}
+	hit := "TRAFIENIE"
+	if event.Type == gamepb.EventType_MISS {
+		hit = "PUDŁO"
+	}
+
+	switch {
+	case event.Type == gamepb.EventType_TAKEN:
+		g.yourTurn = true
+		writeLog(fmt.Sprintf("%sPowtórzony strzał w (%s,%d). Podaj inne współrzędne.", prefix, toLetter(event.X+1), event.Y+1))
+	case event.UserId2 == s.userId:
+		g.yourTurn = true
+		g.unread = !shown
+		writeLog(fmt.Sprintf("%sPrzeciwnik strzelił w (%s,%d) - %s", prefix, toLetter(event.X+1), event.Y+1, hit))
+		if shown {
+			writeLog("Twój ruch! Podaj współrzędne (np. B4)...")
+		}
+	default:
+		g.yourTurn = false
+		writeLog(fmt.Sprintf("%sStrzeliłeś w (%s,%d) - %s", prefix, toLetter(event.X+1), event.Y+1, hit))
+		if shown {
+			writeLog("Czekaj na ruch przeciwnika...")
+		}
+	}
+
+	if shown {
+		s.draw(g)
+	}
+	s.drawHeader()
+}
+
+var errorMessages = map[gamepb.ErrorCode]string{
+	gamepb.ErrorCode_OUT_OF_BOUNDS:  "Strzał poza planszą. Podaj inne współrzędne.",
+	gamepb.ErrorCode_NOT_YOUR_TURN:  "Teraz ruch przeciwnika.",
+	gamepb.ErrorCode_GAME_NOT_FOUND: "Nie ma takiej gry.",
+	gamepb.ErrorCode_GAME_FINISHED:  "Ta gra już się zakończyła.",
+	gamepb.ErrorCode_INTERNAL:       "Błąd serwera. Spróbuj ponownie.",
+	gamepb.ErrorCode_NOT_A_PLAYER:   "Nie grasz w tej grze.",
+	gamepb.ErrorCode_RATE_LIMITED:   "Za dużo ruchów naraz. Zwolnij i spróbuj ponownie.",
+}
+
+// handleError reports an event the server rejected. The shot was not
+// recorded, so after a shot outside the board, one sent too fast or a server
+// error it is still the player's turn.
+func (s *session) handleError(event *gamepb.GameEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	message, ok := errorMessages[event.GetErrorCode()]
+	if !ok {
+		message = event.GetErrorMessage()
+	}
+	i := s.find(event.GameId)
+	if i < 0 {
+		writeLog(message)
+		return
+	}
+	g := s.games[i]
+	if i != s.current {
+		message = fmt.Sprintf("[%d: %s] %s", i+1, g.enemy, message)
+	}
+	writeLog(message)
+	switch event.GetErrorCode() {
+	case gamepb.ErrorCode_OUT_OF_BOUNDS, gamepb.ErrorCode_INTERNAL, gamepb.ErrorCode_RATE_LIMITED:
+		g.yourTurn = true
+		s.drawHeader()
+	}
+}
+
+// gameOver removes a finished game and reports whether it was the last one.
+func (s *session) gameOver(event *gamepb.GameEvent) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(event.GameId)
+	if i < 0 {
+		return false
+	}
+	g := s.games[i]
+	won := event.UserId1 == s.userId
+	if won {
+		writeLog(fmt.Sprintf("Wygrałeś z %s!", g.enemy))
+	} else {
+		writeLog(fmt.Sprintf("Przegrałeś z %s.", g.enemy))
+	}
+
+	s.games = append(s.games[:i], s.games[i+1:]...)
+	if len(s.games) == 0 {
+		if won {
+			setHeader("WYGRANA")
+		} else {
+			setHeader("PRZEGRANA")
+		}
+		app.QueueUpdateDraw(func() {
+			app.SetFocus(nil)
+		})
+		return true
+	}
+
+	if i == s.current {
+		s.current = 0
+		writeLog(fmt.Sprintf("Przełączono na grę z %s.", s.games[0].enemy))
+		s.draw(s.games[0])
+	} else if i < s.current {
+		s.current--
+	}
+	s.drawHeader()
+	return false
+}
+
+// show switches the boards to the game at index i.
+func (s *session) show(i int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.current = i
+	g := s.games[i]
+	g.unread = false
+	if len(s.games) > 1 {
+		writeLog(fmt.Sprintf("Gra z %s.", g.enemy))
+	}
+	if g.yourTurn {
+		writeLog("Twój ruch! Podaj współrzędne (np. B4)...")
+	}
+	s.draw(g)
+	s.drawHeader()
+}
+
+func (s *session) find(gameId string) int {
+	for i, g := range s.games {
+		if g.id == gameId {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *session) draw(g *activeGame) {
+	ctx, span := tracing.Start(context.Background(), "client.DrawGame")
+	defer span.End()
+
+	moves, _ := (*s.client).GetMoves(ctx, &gamepb.GetMovesRequest{GameId: g.id, UserId: s.userId})
+	enemyMoves, _ := (*s.client).GetMoves(ctx, &gamepb.GetMovesRequest{GameId: g.id, UserId: g.enemyId})
+	drawEnemyTable(moves, g.boardSize, app, firstTable)
+	drawUserTable(g.ships, enemyMoves, g.boardSize, app, secondTable)
+}
+
+// drawHeader shows the turn in the current game and, with more than one
+// game, a tab for each: "*" marks games where it is the player's turn, "!"
+// games where the opponent moved since they were last shown.
+func (s *session) drawHeader() {
+	g := s.games[s.current]
+	text, color := "TURA PRZECIWNIKA", tcell.Color196
+	if g.yourTurn {
+		text, color = "TWOJA TURA", tcell.Color40
+	}
+
+	if len(s.games) > 1 {
+		tabs := make([]string, 0, len(s.games))
+		for i, g := range s.games {
+			tab := fmt.Sprintf("%d: %s", i+1, g.enemy)
+			if i == s.current {
+				tab = "[" + tab + "]"
+			}
+			if g.yourTurn {
+				tab += "*"
+			}
+			if g.unread {
+				tab += "!"
+			}
+			tabs = append(tabs, tab)
+		}
+		text += "   " + strings.Join(tabs, "  ")
+	}
+
+	setHeader(text)
+	app.QueueUpdateDraw(func() {
+		header.SetTextColor(color)
+	})
+}
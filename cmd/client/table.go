@@ -8,20 +8,20 @@ import (
 	"github.com/rivo/tview"
 )
 
-func drawUserTable(userShips *gamepb.GetShipsResponse, enemyMoves *gamepb.GetMovesResponse, app *tview.Application, view *tview.TextView) {
+func drawUserTable(userShips *gamepb.GetShipsResponse, enemyMoves *gamepb.GetMovesResponse, boardSize int32, app *tview.Application, view *tview.TextView) {
 	var b strings.Builder
-	columns := []rune{'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H'}
+	size := int(boardSize)
 
 	b.WriteString("Twoja plansza:\n")
 	b.WriteString("   ")
-	for _, col := range columns {
-		fmt.Fprintf(&b, " %c ", col)
+	for col := 0; col < size; col++ {
+		fmt.Fprintf(&b, " %c ", 'A'+col)
 	}
 	b.WriteString("\n")
 
-	for newY := 0; newY < 8; newY++ {
+	for newY := 0; newY < size; newY++ {
 		fmt.Fprintf(&b, "%2d ", newY+1)
-		for newX := 0; newX < 8; newX++ {
+		for newX := 0; newX < size; newX++ {
 			if move, ok := tryGetMove(enemyMoves.Moves, int32(newX), int32(newY)); ok {
 				if move.Hit {
 					b.WriteString("[X]")
@@ -43,20 +43,20 @@ func drawUserTable(userShips *gamepb.GetShipsResponse, enemyMoves *gamepb.GetMov
 	})
 }
 
-func drawEnemyTable(moves *gamepb.GetMovesResponse, app *tview.Application, view *tview.TextView) {
+func drawEnemyTable(moves *gamepb.GetMovesResponse, boardSize int32, app *tview.Application, view *tview.TextView) {
 	var b strings.Builder
-	columns := []rune{'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H'}
+	size := int(boardSize)
 
 	b.WriteString("Plansza przeciwnika:\n")
 	b.WriteString("   ")
-	for _, col := range columns {
-		fmt.Fprintf(&b, " %c ", col)
+	for col := 0; col < size; col++ {
+		fmt.Fprintf(&b, " %c ", 'A'+col)
 	}
 	b.WriteString("\n")
 
-	for newY := 0; newY < 8; newY++ {
+	for newY := 0; newY < size; newY++ {
 		fmt.Fprintf(&b, "%2d ", newY+1)
-		for newX := 0; newX < 8; newX++ {
+		for newX := 0; newX < size; newX++ {
 			move, ok := tryGetMove(moves.Moves, int32(newX), int32(newY))
 			if ok {
 				if move.Hit {
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gosukretess/battleships/internal/tlsutil"
+)
+
+func runDevCerts(args []string) {
+	fs := flag.NewFlagSet("devcerts", flag.ExitOnError)
+	dir := fs.String("out", "certs", "directory to write the certificates to")
+	hosts := fs.String("hosts", "localhost,127.0.0.1", "comma separated host names and IPs for the server certificate")
+	fs.Parse(args)
+
+	var hostList []string
+	for _, host := range strings.Split(*hosts, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hostList = append(hostList, host)
+		}
+	}
+	if len(hostList) == 0 {
+		log.Fatal("at least one host is required")
+	}
+
+	if err := tlsutil.GenerateDevCerts(*dir, hostList); err != nil {
+		log.Fatalf("failed to generate certificates: %v", err)
+	}
+	fmt.Printf("Wrote development CA, server and client certificates to %s\n", *dir)
+}
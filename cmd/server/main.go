@@ -1,32 +1,224 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
+	"log/slog"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gosukretess/battleships/internal"
+	"github.com/gosukretess/battleships/internal/auth"
+	"github.com/gosukretess/battleships/internal/config"
+	"github.com/gosukretess/battleships/internal/game"
+	"github.com/gosukretess/battleships/internal/gateway"
+	"github.com/gosukretess/battleships/internal/logging"
+	"github.com/gosukretess/battleships/internal/metrics"
+	"github.com/gosukretess/battleships/internal/ratelimit"
+	"github.com/gosukretess/battleships/internal/tracing"
+	"github.com/gosukretess/battleships/internal/web"
 	"github.com/gosukretess/battleships/proto/gamepb"
 	"github.com/gosukretess/battleships/proto/userpb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
-	lis, err := net.Listen("tcp", ":50051")
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			runMigrate(os.Args[2:])
+			return
+		case "devcerts":
+			runDevCerts(os.Args[2:])
+			return
+		case "passwd":
+			runPasswd(os.Args[2:])
+			return
+		}
+	}
+
+	printConfig := flag.Bool("print-config", false, "print the effective configuration and exit")
+	cfg, err := config.LoadServer(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	if *printConfig {
+		if err := config.Print(os.Stdout, cfg.Redacted()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := logging.Setup(cfg.Log); err != nil {
+		log.Fatal(err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, "battleships-server")
+	if err != nil {
+		log.Fatalf("failed to set up tracing: %v", err)
+	}
+	defer flushTraces(shutdownTracing)
+
+	lis, err := net.Listen("tcp", cfg.Listen)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	grpcServer := grpc.NewServer()
 
-	srv, err := internal.InitializeServers("database.db")
+	srv, cleanup, err := internal.InitializeServers(cfg.Database, cfg.Auth, cfg.Game, cfg.Notify)
 	if err != nil {
 		log.Fatalf("failed to init server: %v", err)
 	}
+	defer cleanup()
+
+	limiter := ratelimit.New(cfg.RateLimit)
+	opts := []grpc.ServerOption{
+		grpc.ConnectionTimeout(cfg.Timeouts.Connection),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			logging.UnaryServerInterceptor(),
+			metrics.UnaryServerInterceptor(),
+			limiter.UnaryServerInterceptor(),
+			auth.UnaryServerInterceptor(srv.UserServer),
+			limiter.UserUnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			logging.StreamServerInterceptor(),
+			metrics.StreamServerInterceptor(),
+			limiter.StreamServerInterceptor(),
+			auth.StreamServerInterceptor(srv.UserServer),
+			limiter.UserStreamServerInterceptor(),
+		),
+	}
+	if cfg.TLS.IsEnabled() {
+		tlsConfig, err := cfg.TLS.TLSConfig()
+		if err != nil {
+			log.Fatalf("failed to load TLS configuration: %v", err)
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	grpcServer := grpc.NewServer(opts...)
 
 	userpb.RegisterUserServiceServer(grpcServer, srv.UserServer)
 	gamepb.RegisterGameServiceServer(grpcServer, srv.GameServer)
+	healthpb.RegisterHealthServer(grpcServer, srv.Health.Server())
+	if cfg.Reflection {
+		reflection.Register(grpcServer)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go srv.Health.Run(ctx)
+	go srv.GameServer.RunTurnTimeouts(ctx)
+
+	var metricsServer *http.Server
+	if cfg.Metrics.Listen != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsServer = startHTTPServer("metrics", cfg.Metrics.Listen, mux)
+	}
+
+	var gatewayServer *http.Server
+	if cfg.Gateway.Listen != "" {
+		gw, err := gateway.New(cfg.Listen, cfg.Gateway)
+		if err != nil {
+			log.Fatalf("failed to set up gateway: %v", err)
+		}
+		defer gw.Close()
+
+		mux := http.NewServeMux()
+		mux.Handle("/v1/", gw)
+		mux.Handle("/openapi.json", gw)
+		bridge := web.NewBridge(srv.GameServer, srv.UserServer, limiter)
+		mux.Handle("GET /v1/games/{game_id}/ws", bridge)
+		mux.Handle("GET /v1/ws", bridge)
+		mux.Handle("/", web.Client())
+		gatewayServer = startHTTPServer("gateway", cfg.Gateway.Listen, mux)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("gRPC server listening", "address", cfg.Listen)
+		serveErr <- grpcServer.Serve(lis)
+	}()
+
+	select {
+	case err := <-serveErr:
+		slog.Error("failed to serve", "error", err)
+	case <-ctx.Done():
+		slog.Info("shutting down")
+	}
+	srv.Health.Shutdown()
+	srv.UserServer.Shutdown()
+	if gatewayServer != nil {
+		shutdownHTTPServer(gatewayServer, cfg.Timeouts.Shutdown)
+	}
+	shutdown(grpcServer, srv.GameServer, cfg.Timeouts.Shutdown)
+	if metricsServer != nil {
+		metricsServer.Close()
+	}
+}
+
+func startHTTPServer(name, addr string, handler http.Handler) *http.Server {
+	server := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		slog.Info(name+" server listening", "address", addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error(name+" server failed", "error", err)
+		}
+	}()
+	return server
+}
+
+// shutdownHTTPServer lets REST calls in flight finish before the gRPC server
+// they are forwarded to goes away.
+func shutdownHTTPServer(server *http.Server, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Warn("gateway did not shut down cleanly", "error", err)
+	}
+}
+
+// shutdown refuses new connections and calls, lets running games finish their
+// current move and closes the remaining streams, forcing them once the timeout expires.
+func shutdown(grpcServer *grpc.Server, gameServer *game.Server, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	if err := gameServer.Shutdown(ctx); err != nil {
+		slog.Warn("game streams did not drain", "error", err)
+	}
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		slog.Warn("shutdown timed out, closing remaining connections")
+		grpcServer.Stop()
+		<-stopped
+	}
+	slog.Info("server stopped")
+}
 
-	log.Println("gRPC runs at port :50051")
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+func flushTraces(shutdown func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		slog.Warn("cannot flush traces", "error", err)
 	}
 }
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/gosukretess/battleships/internal/config"
+	"github.com/gosukretess/battleships/internal/database"
+	"github.com/gosukretess/battleships/internal/user"
+)
+
+// runPasswd sets the password of an existing account, e.g. one created before
+// passwords were required. The password is read from the first line of stdin.
+func runPasswd(args []string) {
+	fs := flag.NewFlagSet("passwd", flag.ExitOnError)
+	email := fs.String("email", "", "email of the user whose password to set")
+	cfg, err := config.LoadServer(fs, args)
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	if *email == "" {
+		log.Fatal("-email is required")
+	}
+
+	fmt.Fprint(os.Stderr, "New password: ")
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		log.Fatalf("cannot read password: %v", err)
+	}
+	password = strings.TrimRight(password, "\r\n")
+	if err := user.ValidatePassword(password); err != nil {
+		log.Fatal(err)
+	}
+
+	// NewDB applies pending migrations, which add the password columns.
+	db, cleanup, err := database.NewDB(cfg.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	store := user.NewStore(db)
+	u, err := store.FindUserByEmail(ctx, strings.ToLower(strings.TrimSpace(*email)))
+	if err != nil {
+		log.Fatalf("cannot find user %s: %v", *email, err)
+	}
+	hash, err := user.HashPassword(password)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := store.SetPassword(ctx, u.Id, hash); err != nil {
+		log.Fatal(err)
+	}
+	if err := store.DeleteSessions(ctx, u.Id); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Password set for %s, existing sessions ended\n", u.Email)
+}
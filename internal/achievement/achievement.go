@@ -0,0 +1,62 @@
+// Package achievement lists the achievements players can unlock. They are
+// awarded by the game server and listed by the user server.
+package achievement
+
+import (
+	"time"
+
+	"github.com/gosukretess/battleships/proto/gamepb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	FirstWin      = "FIRST_WIN"
+	Sharpshooter  = "SHARPSHOOTER"
+	CarrierStrike = "CARRIER_STRIKE"
+	QuickWin      = "QUICK_WIN"
+	WinStreak     = "WIN_STREAK_10"
+
+	// StreakLength is the number of games in a row WinStreak asks for.
+	StreakLength = 10
+	// MissesInARow is the number of misses in a row that rule out Sharpshooter.
+	MissesInARow = 3
+)
+
+type Achievement struct {
+	Id          string
+	Name        string
+	Description string
+}
+
+var all = []Achievement{
+	{FirstWin, "First blood", "Win a game."},
+	{Sharpshooter, "Sharpshooter", "Sink the whole enemy fleet without missing three times in a row."},
+	{CarrierStrike, "Carrier strike", "Sink a carrier with consecutive hits."},
+	{QuickWin, "Blitz", "Sink the whole enemy fleet within the shot limit of the rule set."},
+	{WinStreak, "Unbeatable", "Win 10 games in a row."},
+}
+
+// All returns every achievement in the order clients show them.
+func All() []Achievement {
+	return append([]Achievement(nil), all...)
+}
+
+func Lookup(id string) (Achievement, bool) {
+	for _, a := range all {
+		if a.Id == id {
+			return a, true
+		}
+	}
+	return Achievement{}, false
+}
+
+// ToProto describes an achievement unlocked by a shot or the end of gameId.
+func (a Achievement) ToProto(gameId string, unlocked time.Time) *gamepb.Achievement {
+	return &gamepb.Achievement{
+		Id:          a.Id,
+		Name:        a.Name,
+		Description: a.Description,
+		GameId:      gameId,
+		Unlocked:    timestamppb.New(unlocked),
+	}
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gosukretess/battleships/internal/logging"
+	"github.com/gosukretess/battleships/proto/gamepb"
+	"github.com/gosukretess/battleships/proto/userpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Header carries the session token as "Bearer <token>".
+const Header = "authorization"
+
+// Authenticator resolves a session token to the id of the user it belongs to.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (string, error)
+}
+
+// publicMethods can be called without a session. Everything that acts on
+// behalf of a player needs one.
+var publicMethods = map[string]bool{
+	userpb.UserService_CreateUser_FullMethodName:       true,
+	userpb.UserService_Login_FullMethodName:            true,
+	userpb.UserService_GetUser_FullMethodName:          true,
+	userpb.UserService_GetUserStats_FullMethodName:     true,
+	gamepb.GameService_GetLeaderboard_FullMethodName:   true,
+	gamepb.GameService_GetRatingHistory_FullMethodName: true,
+}
+
+func isPublic(method string) bool {
+	return publicMethods[method] ||
+		strings.HasPrefix(method, "/grpc.health.v1.Health/") ||
+		strings.HasPrefix(method, "/grpc.reflection.")
+}
+
+type userIDKey struct{}
+
+func WithUserID(ctx context.Context, userId string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userId)
+}
+
+// UserID returns the id of the authenticated caller.
+func UserID(ctx context.Context) (string, bool) {
+	userId, ok := ctx.Value(userIDKey{}).(string)
+	return userId, ok && userId != ""
+}
+
+// Token returns the session token sent with an incoming call.
+func Token(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(Header)
+	if len(values) == 0 {
+		return ""
+	}
+	token, found := strings.CutPrefix(values[0], "Bearer ")
+	if !found {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+func UnaryServerInterceptor(a Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, a, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamServerInterceptor(a Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), a, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticate adds the caller to ctx. Public methods accept calls without a
+// valid session, every other method fails with Unauthenticated.
+func authenticate(ctx context.Context, a Authenticator, method string) (context.Context, error) {
+	token := Token(ctx)
+	if token == "" {
+		if isPublic(method) {
+			return ctx, nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "missing session token")
+	}
+
+	userId, err := a.Authenticate(ctx, token)
+	if err != nil {
+		if isPublic(method) {
+			return ctx, nil
+		}
+		return nil, err
+	}
+	logging.AddAttrs(ctx, "caller_id", userId)
+	return WithUserID(ctx, userId), nil
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
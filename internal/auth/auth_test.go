@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gosukretess/battleships/proto/gamepb"
+	"github.com/gosukretess/battleships/proto/userpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// sessions authenticates a single token, of alice.
+type sessions struct{}
+
+func (sessions) Authenticate(ctx context.Context, token string) (string, error) {
+	if token != "secret" {
+		return "", status.Error(codes.Unauthenticated, "invalid or expired session token")
+	}
+	return "alice", nil
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	const (
+		private = gamepb.GameService_CreateGame_FullMethodName
+		public  = userpb.UserService_Login_FullMethodName
+	)
+	tests := []struct {
+		method, header string
+		want           codes.Code
+		caller         string
+	}{
+		{method: private, header: "Bearer secret", caller: "alice"},
+		{method: private, header: "Bearer  secret ", caller: "alice"},
+		{method: private, want: codes.Unauthenticated},
+		{method: private, header: "secret", want: codes.Unauthenticated},
+		{method: private, header: "Bearer wrong", want: codes.Unauthenticated},
+		{method: public, header: "Bearer secret", caller: "alice"},
+		{method: public},
+		{method: public, header: "Bearer wrong"},
+		{method: "/grpc.health.v1.Health/Check"},
+	}
+	interceptor := UnaryServerInterceptor(sessions{})
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.header != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(Header, tt.header))
+		}
+		var caller string
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req any) (any, error) {
+			caller, _ = UserID(ctx)
+			return nil, nil
+		})
+		if status.Code(err) != tt.want {
+			t.Errorf("%s with %q: error %v, want %v", tt.method, tt.header, err, tt.want)
+		}
+		if caller != tt.caller {
+			t.Errorf("%s with %q: caller %q, want %q", tt.method, tt.header, caller, tt.caller)
+		}
+	}
+}
+
+// stream is a server stream with nothing but a context.
+type stream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *stream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := StreamServerInterceptor(sessions{})
+	info := &grpc.StreamServerInfo{FullMethod: gamepb.GameService_PlayerMove_FullMethodName, IsClientStream: true, IsServerStream: true}
+
+	err := interceptor(nil, &stream{ctx: context.Background()}, info, func(srv any, s grpc.ServerStream) error {
+		t.Error("stream opened without a session")
+		return nil
+	})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("stream without a session: error %v, want Unauthenticated", err)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(Header, "Bearer secret"))
+	err = interceptor(nil, &stream{ctx: ctx}, info, func(srv any, s grpc.ServerStream) error {
+		if caller, _ := UserID(s.Context()); caller != "alice" {
+			t.Errorf("stream caller %q, want alice", caller)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/gosukretess/battleships/internal/logging"
+	"github.com/gosukretess/battleships/internal/tlsutil"
+	"github.com/gosukretess/battleships/internal/tracing"
+)
+
+const clientEnvPrefix = "BATTLESHIPS_CLIENT_"
+
+type Client struct {
+	Server   string               `yaml:"server" toml:"server"`
+	TLS      tlsutil.ClientConfig `yaml:"tls" toml:"tls"`
+	Log      logging.Config       `yaml:"log" toml:"log"`
+	Timeouts ClientTimeouts       `yaml:"timeouts" toml:"timeouts"`
+	Tracing  tracing.Config       `yaml:"tracing" toml:"tracing"`
+}
+
+type ClientTimeouts struct {
+	// Request limits every unary call. The game stream is not affected.
+	Request time.Duration `yaml:"request" toml:"request"`
+}
+
+func DefaultClient() *Client {
+	return &Client{
+		Server:   "localhost:50051",
+		Log:      logging.Config{Level: "info", Format: "text"},
+		Timeouts: ClientTimeouts{Request: 10 * time.Second},
+		Tracing:  tracing.Config{Exporter: tracing.ExporterNone},
+	}
+}
+
+// LoadClient works like LoadServer for the client settings.
+func LoadClient(fs *flag.FlagSet, args []string) (*Client, error) {
+	cfg := DefaultClient()
+	settings := []setting{
+		{"server", "address of the game server", &cfg.Server},
+		{"tls", "connect using TLS", &cfg.TLS.Enabled},
+		{"tls-ca", "CA bundle used to verify the server certificate", &cfg.TLS.CAFile},
+		{"tls-cert", "client certificate for servers that require one", &cfg.TLS.CertFile},
+		{"tls-key", "private key of the client certificate", &cfg.TLS.KeyFile},
+		{"tls-server-name", "override the server name used to verify the certificate", &cfg.TLS.ServerName},
+		{"log-level", "log level: debug, info, warn or error", &cfg.Log.Level},
+		{"log-format", "log format: text or json", &cfg.Log.Format},
+		{"timeout", "timeout for unary requests", &cfg.Timeouts.Request},
+		{"trace-exporter", "trace exporter: none, stdout or otlp", &cfg.Tracing.Exporter},
+		{"trace-endpoint", "OTLP gRPC collector address (default localhost:4317)", &cfg.Tracing.Endpoint},
+		{"trace-insecure", "connect to the OTLP collector without TLS", &cfg.Tracing.Insecure},
+		{"trace-file", "write stdout traces to this file instead, keeping the terminal clean", &cfg.Tracing.File},
+	}
+
+	if err := load(fs, args, clientEnvPrefix, cfg, settings); err != nil {
+		return nil, err
+	}
+	return cfg, cfg.Validate()
+}
+
+func (c *Client) Validate() error {
+	if c.Server == "" {
+		return errors.New("server address must not be empty")
+	}
+	if err := c.TLS.Validate(); err != nil {
+		return err
+	}
+	if c.Timeouts.Request < 0 {
+		return errors.New("request timeout must not be negative")
+	}
+	if err := c.Log.Validate(); err != nil {
+		return err
+	}
+	if err := c.Tracing.Validate(); err != nil {
+		return err
+	}
+	return nil
+}
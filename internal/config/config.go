@@ -0,0 +1,172 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// setting binds a config field to a command line flag and an environment variable.
+type setting struct {
+	flag  string
+	usage string
+	value any
+}
+
+// rawFlag keeps the flag text as given so it can be applied after the config
+// file and environment have been read.
+type rawFlag struct {
+	value  string
+	isBool bool
+}
+
+func (f *rawFlag) String() string     { return f.value }
+func (f *rawFlag) Set(v string) error { f.value = v; return nil }
+func (f *rawFlag) IsBoolFlag() bool   { return f.isBool }
+
+// load fills cfg from, in increasing order of precedence: the values already
+// in cfg, the config file, environment variables and command line flags.
+func load(fs *flag.FlagSet, args []string, envPrefix string, cfg any, settings []setting) error {
+	configFile := fs.String("config", "", "path to a YAML or TOML config file (env "+envPrefix+"CONFIG)")
+	for _, s := range settings {
+		_, isBool := s.value.(*bool)
+		raw := &rawFlag{value: formatValue(s.value), isBool: isBool}
+		fs.Var(raw, s.flag, fmt.Sprintf("%s (env %s)", s.usage, envName(envPrefix, s.flag)))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := *configFile
+	if path == "" {
+		path = os.Getenv(envPrefix + "CONFIG")
+	}
+	if path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range settings {
+		name := envName(envPrefix, s.flag)
+		if v, ok := os.LookupEnv(name); ok {
+			if err := setValue(s.value, v); err != nil {
+				return fmt.Errorf("invalid value %q for %s: %w", v, name, err)
+			}
+		}
+	}
+
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.flag == f.Name && flagErr == nil {
+				if err := setValue(s.value, f.Value.String()); err != nil {
+					flagErr = fmt.Errorf("invalid value %q for -%s: %w", f.Value.String(), f.Name, err)
+				}
+			}
+		}
+	})
+	return flagErr
+}
+
+func loadFile(path string, cfg any) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		md, err := toml.Decode(string(content), cfg)
+		if err != nil {
+			return fmt.Errorf("cannot parse %s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("cannot parse %s: unknown key %q", path, undecoded[0].String())
+		}
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil && err != io.EOF {
+			return fmt.Errorf("cannot parse %s: %w", path, err)
+		}
+	default:
+		return fmt.Errorf("unsupported config file format: %s", path)
+	}
+	return nil
+}
+
+// Print writes cfg as YAML, in the same shape the config file accepts.
+func Print(w io.Writer, cfg any) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(cfg); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func envName(prefix, flagName string) string {
+	return prefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case *string:
+		return *v
+	case *bool:
+		return strconv.FormatBool(*v)
+	case *int:
+		return strconv.Itoa(*v)
+	case *float64:
+		return strconv.FormatFloat(*v, 'g', -1, 64)
+	case *time.Duration:
+		return v.String()
+	default:
+		panic(fmt.Sprintf("config: unsupported setting type %T", value))
+	}
+}
+
+func setValue(value any, text string) error {
+	switch v := value.(type) {
+	case *string:
+		*v = text
+	case *bool:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		*v = parsed
+	case *int:
+		parsed, err := strconv.Atoi(text)
+		if err != nil {
+			return err
+		}
+		*v = parsed
+	case *float64:
+		parsed, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return err
+		}
+		*v = parsed
+	case *time.Duration:
+		parsed, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		*v = parsed
+	default:
+		panic(fmt.Sprintf("config: unsupported setting type %T", value))
+	}
+	return nil
+}
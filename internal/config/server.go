@@ -0,0 +1,221 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gosukretess/battleships/internal/database"
+	"github.com/gosukretess/battleships/internal/game"
+	"github.com/gosukretess/battleships/internal/gateway"
+	"github.com/gosukretess/battleships/internal/logging"
+	"github.com/gosukretess/battleships/internal/notify"
+	"github.com/gosukretess/battleships/internal/ratelimit"
+	"github.com/gosukretess/battleships/internal/tlsutil"
+	"github.com/gosukretess/battleships/internal/tracing"
+	"github.com/gosukretess/battleships/internal/user"
+)
+
+const serverEnvPrefix = "BATTLESHIPS_"
+
+type Server struct {
+	Listen   string               `yaml:"listen" toml:"listen"`
+	Database database.Config      `yaml:"database" toml:"database"`
+	TLS      tlsutil.ServerConfig `yaml:"tls" toml:"tls"`
+	Log      logging.Config       `yaml:"log" toml:"log"`
+	Timeouts ServerTimeouts       `yaml:"timeouts" toml:"timeouts"`
+	Game     game.Config          `yaml:"game" toml:"game"`
+	Auth     user.Config          `yaml:"auth" toml:"auth"`
+	Metrics  Metrics              `yaml:"metrics" toml:"metrics"`
+	Gateway  gateway.Config       `yaml:"gateway" toml:"gateway"`
+	Tracing  tracing.Config       `yaml:"tracing" toml:"tracing"`
+	// Notify tells players of correspondence games when it is their turn.
+	Notify notify.Config `yaml:"notify" toml:"notify"`
+	// RateLimit protects the server from clients flooding it with calls.
+	RateLimit ratelimit.Config `yaml:"rate_limit" toml:"rate_limit"`
+	// Reflection exposes the gRPC reflection service for tools like grpcurl.
+	Reflection bool `yaml:"reflection" toml:"reflection"`
+}
+
+type Metrics struct {
+	// Listen is the address of the HTTP server exposing /metrics. Empty disables it.
+	Listen string `yaml:"listen" toml:"listen"`
+}
+
+type ServerTimeouts struct {
+	// Connection limits how long establishing a new connection may take.
+	Connection time.Duration `yaml:"connection" toml:"connection"`
+	// Shutdown limits how long the server waits for games to drain on exit.
+	Shutdown time.Duration `yaml:"shutdown" toml:"shutdown"`
+}
+
+func DefaultServer() *Server {
+	return &Server{
+		Listen:   ":50051",
+		Database: database.Config{DSN: "database.db"},
+		Log:      logging.Config{Level: "info", Format: "text"},
+		Timeouts: ServerTimeouts{Connection: 20 * time.Second, Shutdown: 10 * time.Second},
+		Game:     game.Config{DefaultRules: game.DefaultRules, TurnTimeout: game.DefaultTurnTimeout},
+		Auth:     user.Config{SessionTTL: 7 * 24 * time.Hour, MaxFailedLogins: 5, LockoutDuration: 15 * time.Minute},
+		Tracing:  tracing.Config{Exporter: tracing.ExporterNone},
+		RateLimit: ratelimit.Config{
+			Default: ratelimit.MethodLimits{
+				PerIP:   ratelimit.Limit{Rate: 20, Burst: 40},
+				PerUser: ratelimit.Limit{Rate: 10, Burst: 20},
+			},
+			Methods: map[string]ratelimit.MethodLimits{
+				"CreateUser": {PerIP: ratelimit.Limit{Rate: 0.1, Burst: 5}},
+				"Login":      {PerIP: ratelimit.Limit{Rate: 0.5, Burst: 10}},
+				"CreateGame": {PerIP: ratelimit.Limit{Rate: 0.5, Burst: 5}, PerUser: ratelimit.Limit{Rate: 0.2, Burst: 3}},
+			},
+			StreamEvents: ratelimit.Limit{Rate: 5, Burst: 10},
+		},
+	}
+}
+
+// LoadServer registers the server settings on fs, parses args and returns the
+// resulting configuration. Flags take precedence over environment variables,
+// which take precedence over the config file.
+func LoadServer(fs *flag.FlagSet, args []string) (*Server, error) {
+	cfg := DefaultServer()
+	settings := []setting{
+		{"listen", "address to listen on", &cfg.Listen},
+		{"db", "SQLite database path or postgres:// connection URL", &cfg.Database.DSN},
+		{"db-backend", "database backend: sqlite or postgres (derived from -db when empty)", &cfg.Database.Backend},
+		{"tls-cert", "TLS certificate file", &cfg.TLS.CertFile},
+		{"tls-key", "TLS private key file", &cfg.TLS.KeyFile},
+		{"tls-client-ca", "CA bundle used to verify client certificates; enables client authentication", &cfg.TLS.ClientCAFile},
+		{"tls-client-auth", "client certificate policy when -tls-client-ca is set: require or optional", &cfg.TLS.ClientAuth},
+		{"log-level", "log level: debug, info, warn or error", &cfg.Log.Level},
+		{"log-format", "log format: text or json", &cfg.Log.Format},
+		{"connection-timeout", "timeout for establishing new connections", &cfg.Timeouts.Connection},
+		{"shutdown-timeout", "how long to wait for in-flight moves and streams on shutdown", &cfg.Timeouts.Shutdown},
+		{"metrics-listen", "address of the Prometheus /metrics HTTP endpoint, empty to disable", &cfg.Metrics.Listen},
+		{"gateway-listen", "address of the REST/JSON gateway, empty to disable", &cfg.Gateway.Listen},
+		{"trace-exporter", "trace exporter: none, stdout or otlp", &cfg.Tracing.Exporter},
+		{"trace-endpoint", "OTLP gRPC collector address (default localhost:4317)", &cfg.Tracing.Endpoint},
+		{"trace-insecure", "connect to the OTLP collector without TLS", &cfg.Tracing.Insecure},
+		{"rate-limit-ip", "calls per second allowed from one IP address for each RPC, 0 to disable", &cfg.RateLimit.Default.PerIP.Rate},
+		{"rate-limit-ip-burst", "calls allowed at once from one IP address", &cfg.RateLimit.Default.PerIP.Burst},
+		{"rate-limit-user", "calls per second allowed for one user for each RPC, 0 to disable", &cfg.RateLimit.Default.PerUser.Rate},
+		{"rate-limit-user-burst", "calls allowed at once for one user", &cfg.RateLimit.Default.PerUser.Burst},
+		{"rate-limit-stream", "events per second a user may send on their game streams, 0 to disable", &cfg.RateLimit.StreamEvents.Rate},
+		{"rate-limit-stream-burst", "events a user may send at once on their game streams", &cfg.RateLimit.StreamEvents.Burst},
+		{"session-ttl", "how long a session token stays valid after login", &cfg.Auth.SessionTTL},
+		{"max-failed-logins", "wrong passwords in a row that lock an account, 0 to disable lockout", &cfg.Auth.MaxFailedLogins},
+		{"lockout-duration", "how long a locked account rejects logins", &cfg.Auth.LockoutDuration},
+		{"reflection", "enable gRPC server reflection", &cfg.Reflection},
+		{"default-rules", "rule set for games created without one: " + strings.Join(game.RuleSetNames(), ", "), &cfg.Game.DefaultRules},
+		{"turn-timeout", "how long a player in a correspondence game has to move before losing", &cfg.Game.TurnTimeout},
+		{"notify-webhook", "URL that receives correspondence game notifications as JSON, empty to disable", &cfg.Notify.WebhookURL},
+		{"notify-mail-dir", "directory that receives correspondence game notifications as .eml files, empty to disable", &cfg.Notify.MailDir},
+		{"notify-mail-from", "sender of notification emails", &cfg.Notify.MailFrom},
+	}
+
+	if err := load(fs, args, serverEnvPrefix, cfg, settings); err != nil {
+		return nil, err
+	}
+	return cfg, cfg.Validate()
+}
+
+func (c *Server) Validate() error {
+	if c.Listen == "" {
+		return errors.New("listen address must not be empty")
+	}
+	if c.Database.DSN == "" {
+		return errors.New("database DSN must not be empty")
+	}
+	if err := c.TLS.Validate(); err != nil {
+		return err
+	}
+	if err := c.Log.Validate(); err != nil {
+		return err
+	}
+	if err := c.Gateway.Validate(); err != nil {
+		return err
+	}
+	if c.Gateway.Listen != "" && c.TLS.IsEnabled() && !c.Gateway.TLS.IsEnabled() {
+		return errors.New("gateway.tls must be configured when the server uses TLS")
+	}
+	if err := c.Tracing.Validate(); err != nil {
+		return err
+	}
+	if err := c.RateLimit.Validate(); err != nil {
+		return err
+	}
+	if err := c.Auth.Validate(); err != nil {
+		return err
+	}
+	if _, ok := game.LookupRules(c.Game.DefaultRules); !ok {
+		return fmt.Errorf("unknown rule set %q", c.Game.DefaultRules)
+	}
+	if c.Game.TurnTimeout <= 0 {
+		return errors.New("game.turn_timeout must be positive")
+	}
+	if err := c.Notify.Validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Redacted returns a copy that is safe to print, with the database password
+// and any credentials in the notify webhook URL masked.
+func (c *Server) Redacted() *Server {
+	redacted := *c
+	redacted.Database.DSN = redactDSN(c.Database.DSN)
+	if c.Notify.WebhookURL != "" {
+		redacted.Notify.WebhookURL = redactWebhookURL(c.Notify.WebhookURL)
+	}
+	return &redacted
+}
+
+const redactedSecret = "xxxxx"
+
+// dsnPassword matches the password of a keyword/value DSN such as
+// "host=db password=secret", quoted or not.
+var dsnPassword = regexp.MustCompile(`(?i)(\bpassword\s*=\s*)('(?:[^'\\]|\\.)*'|\S*)`)
+
+// redactDSN masks the password of a URL or keyword/value PostgreSQL DSN.
+// SQLite paths are returned as they are.
+func redactDSN(dsn string) string {
+	if !strings.Contains(dsn, "://") {
+		return dsnPassword.ReplaceAllString(dsn, "${1}"+redactedSecret)
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return redactedSecret
+	}
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redactedSecret)
+		}
+	}
+	query := u.Query()
+	if query.Has("password") {
+		query.Set("password", redactedSecret)
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// redactWebhookURL masks the user info and every query value of a webhook
+// URL, where services put their tokens and signatures.
+func redactWebhookURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redactedSecret
+	}
+	if u.User != nil {
+		u.User = url.User(redactedSecret)
+	}
+	query := u.Query()
+	for key := range query {
+		query.Set(key, redactedSecret)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
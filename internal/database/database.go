@@ -0,0 +1,231 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	SQLite   = "sqlite"
+	Postgres = "postgres"
+)
+
+// Foreign keys are enforced per connection in SQLite. Transactions take the
+// write lock up front so concurrent writers queue on busy_timeout instead of
+// failing with SQLITE_BUSY halfway through.
+const sqliteParams = "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_txlock=immediate"
+
+type Config struct {
+	// Backend is "sqlite" or "postgres". When empty it is derived from DSN.
+	Backend string `yaml:"backend" toml:"backend"`
+	DSN     string `yaml:"dsn" toml:"dsn"`
+}
+
+// DB wraps *sql.DB so that stores can write queries with "?" placeholders
+// regardless of the backend they run against.
+type DB struct {
+	*sql.DB
+	Backend string
+}
+
+type Tx struct {
+	*sql.Tx
+	backend string
+}
+
+// Open opens the database described by cfg without touching its schema.
+// Without an explicit backend, a postgres:// or postgresql:// URL selects
+// PostgreSQL and anything else is treated as a path to a SQLite file.
+func Open(cfg Config) (*DB, error) {
+	backend := cfg.Backend
+	if backend == "" {
+		backend = SQLite
+		if strings.HasPrefix(cfg.DSN, "postgres://") || strings.HasPrefix(cfg.DSN, "postgresql://") {
+			backend = Postgres
+		}
+	}
+
+	var driver, source string
+	switch backend {
+	case SQLite:
+		driver, source = "sqlite", cfg.DSN+sqliteParams
+	case Postgres:
+		driver, source = "pgx", cfg.DSN
+	default:
+		return nil, fmt.Errorf("unknown database backend %q", backend)
+	}
+
+	db, err := sql.Open(driver, source)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open db: %w", err)
+	}
+	return &DB{DB: db, Backend: backend}, nil
+}
+
+// NewDB opens the database and applies all pending migrations.
+// The returned cleanup function closes the database.
+func NewDB(cfg Config) (*DB, func(), error) {
+	db, err := Open(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+
+	applied, err := migrator.Up()
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	if applied > 0 {
+		slog.Info("applied database migrations", "count", applied)
+	}
+
+	cleanup := func() {
+		if err := db.Close(); err != nil {
+			slog.Error("cannot close db", "error", err)
+		}
+	}
+	return db, cleanup, nil
+}
+
+func (db *DB) Exec(query string, args ...any) (sql.Result, error) {
+	return db.DB.Exec(rebind(db.Backend, query), args...)
+}
+
+func (db *DB) Query(query string, args ...any) (*sql.Rows, error) {
+	return db.DB.Query(rebind(db.Backend, query), args...)
+}
+
+func (db *DB) QueryRow(query string, args ...any) *sql.Row {
+	return db.DB.QueryRow(rebind(db.Backend, query), args...)
+}
+
+func (db *DB) Begin() (*Tx, error) {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, backend: db.Backend}, nil
+}
+
+func (tx *Tx) Exec(query string, args ...any) (sql.Result, error) {
+	return tx.Tx.Exec(rebind(tx.backend, query), args...)
+}
+
+func (tx *Tx) Query(query string, args ...any) (*sql.Rows, error) {
+	return tx.Tx.Query(rebind(tx.backend, query), args...)
+}
+
+func (tx *Tx) QueryRow(query string, args ...any) *sql.Row {
+	return tx.Tx.QueryRow(rebind(tx.backend, query), args...)
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := db.DB.ExecContext(ctx, rebind(db.Backend, query), args...)
+	recordError(ctx, err)
+	return result, err
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	rows, err := db.DB.QueryContext(ctx, rebind(db.Backend, query), args...)
+	recordError(ctx, err)
+	return rows, err
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return db.DB.QueryRowContext(ctx, rebind(db.Backend, query), args...)
+}
+
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		recordError(ctx, err)
+		return nil, err
+	}
+	return &Tx{Tx: tx, backend: db.Backend}, nil
+}
+
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := tx.Tx.ExecContext(ctx, rebind(tx.backend, query), args...)
+	recordError(ctx, err)
+	return result, err
+}
+
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	rows, err := tx.Tx.QueryContext(ctx, rebind(tx.backend, query), args...)
+	recordError(ctx, err)
+	return rows, err
+}
+
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return tx.Tx.QueryRowContext(ctx, rebind(tx.backend, query), args...)
+}
+
+// recordError marks the span in ctx as failed. Constraint violations the
+// stores turn into domain errors are not failures of the query itself.
+func recordError(ctx context.Context, err error) {
+	if err == nil || IsUniqueViolation(err) {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// rebind rewrites "?" placeholders to "$1", "$2", ... for PostgreSQL. A "?"
+// in a string literal or a quoted identifier is left alone.
+func rebind(backend, query string) string {
+	if backend != Postgres || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	var quote rune
+	for _, r := range query {
+		switch {
+		case quote != 0:
+			// A doubled quote inside the literal closes and reopens it.
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// IsUniqueViolation reports whether err was caused by a duplicate key, in a
+// unique index or in a primary key.
+func IsUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code()
+		return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	}
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,22 @@
+package database
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		backend, query, want string
+	}{
+		{SQLite, "SELECT * FROM users WHERE id = ?", "SELECT * FROM users WHERE id = ?"},
+		{Postgres, "SELECT * FROM users WHERE id = ?", "SELECT * FROM users WHERE id = $1"},
+		{Postgres, "UPDATE users SET name = ? WHERE id = ?", "UPDATE users SET name = $1 WHERE id = $2"},
+		{Postgres, "SELECT 'why?' FROM users WHERE id = ?", "SELECT 'why?' FROM users WHERE id = $1"},
+		{Postgres, "SELECT 'it''s ?', ? FROM users", "SELECT 'it''s ?', $1 FROM users"},
+		{Postgres, `SELECT "odd?column" FROM users WHERE id = ?`, `SELECT "odd?column" FROM users WHERE id = $1`},
+		{Postgres, "SELECT 1", "SELECT 1"},
+	}
+	for _, tt := range tests {
+		if got := rebind(tt.backend, tt.query); got != tt.want {
+			t.Errorf("rebind(%s, %q) = %q, want %q", tt.backend, tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Package dbtest opens throwaway databases of every backend for tests.
+//
+// SQLite databases are files in a temporary directory. PostgreSQL tests run
+// against BATTLESHIPS_TEST_POSTGRES_DSN when it is set, each in a schema of
+// its own that is dropped afterwards. Otherwise a local server is started
+// with initdb and pg_ctl if they are on PATH, and the tests are skipped when
+// they are not.
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gosukretess/battleships/internal/database"
+)
+
+// PostgresDSNEnv names the variable with the DSN of a PostgreSQL server the
+// tests may create schemas in.
+const PostgresDSNEnv = "BATTLESHIPS_TEST_POSTGRES_DSN"
+
+// Backends lists every backend, for tests to run against each of them.
+var Backends = []string{database.SQLite, database.Postgres}
+
+// Run runs test as a subtest for every backend.
+func Run(t *testing.T, test func(t *testing.T, backend string)) {
+	for _, backend := range Backends {
+		t.Run(backend, func(t *testing.T) {
+			test(t, backend)
+		})
+	}
+}
+
+// Open returns an empty database of the backend without running migrations.
+// It is closed and removed when the test ends.
+func Open(t testing.TB, backend string) *database.DB {
+	t.Helper()
+
+	var cfg database.Config
+	switch backend {
+	case database.SQLite:
+		cfg = database.Config{Backend: database.SQLite, DSN: filepath.Join(t.TempDir(), "test.db")}
+	case database.Postgres:
+		cfg = database.Config{Backend: database.Postgres, DSN: postgresSchema(t)}
+	default:
+		t.Fatalf("unknown backend %q", backend)
+	}
+
+	db, err := database.Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// New returns a database of the backend with every migration applied.
+func New(t testing.TB, backend string) *database.DB {
+	t.Helper()
+
+	db := Open(t, backend)
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// postgresSchema creates a schema for the test and returns a DSN that uses it.
+func postgresSchema(t testing.TB) string {
+	t.Helper()
+
+	dsn := os.Getenv(PostgresDSNEnv)
+	if dsn == "" {
+		var err error
+		if dsn, err = localPostgres(); err != nil {
+			t.Skipf("no PostgreSQL: set %s or put initdb and pg_ctl on PATH (%v)", PostgresDSNEnv, err)
+		}
+	}
+
+	admin, err := database.Open(database.Config{Backend: database.Postgres, DSN: dsn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Close()
+
+	schema := "test_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		admin, err := database.Open(database.Config{Backend: database.Postgres, DSN: dsn})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer admin.Close()
+		if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Error(err)
+		}
+	})
+	return withSearchPath(dsn, schema)
+}
+
+// withSearchPath adds search_path to a URL or keyword/value DSN. pgx sends
+// parameters it does not know to the server as runtime settings.
+func withSearchPath(dsn, schema string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		return dsn + sep + "search_path=" + schema
+	}
+	return dsn + " search_path=" + schema
+}
+
+var local struct {
+	once sync.Once
+	dsn  string
+	err  error
+}
+
+// localPostgres starts a PostgreSQL server in a temporary directory, once per
+// test binary. It runs until Main stops it after the tests.
+func localPostgres() (string, error) {
+	local.once.Do(func() {
+		local.dsn, local.err = startLocal()
+	})
+	return local.dsn, local.err
+}
+
+func startLocal() (string, error) {
+	initdb, err := exec.LookPath("initdb")
+	if err != nil {
+		return "", err
+	}
+	pgCtl, err := exec.LookPath("pg_ctl")
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := os.MkdirTemp("", "battleships-pg-")
+	if err != nil {
+		return "", err
+	}
+	data := filepath.Join(dir, "data")
+	if out, err := exec.Command(initdb, "-D", data, "-U", "postgres", "-A", "trust").CombinedOutput(); err != nil {
+		return "", fmt.Errorf("initdb: %v: %s", err, out)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		return "", err
+	}
+	// Listen on localhost only and keep the socket in our directory, which
+	// works without write access to /var/run/postgresql.
+	opts := fmt.Sprintf("-p %d -k %s -c listen_addresses=localhost", port, dir)
+	cmd := exec.Command(pgCtl, "start", "-w", "-D", data, "-l", filepath.Join(dir, "log"), "-o", opts)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("pg_ctl start: %v: %s", err, out)
+	}
+	stopLocal = func() {
+		exec.Command(pgCtl, "stop", "-m", "immediate", "-D", data).Run()
+		os.RemoveAll(dir)
+	}
+
+	dsn := fmt.Sprintf("postgres://postgres@localhost:%d/postgres?sslmode=disable", port)
+	return dsn, waitForPostgres(dsn)
+}
+
+var stopLocal = func() {}
+
+// Main runs the tests of a package and then stops the PostgreSQL server
+// started for them, if any. Call it from TestMain.
+func Main(m *testing.M) {
+	code := m.Run()
+	stopLocal()
+	os.Exit(code)
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func waitForPostgres(dsn string) error {
+	db, err := database.Open(database.Config{Backend: database.Postgres, DSN: dsn})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for {
+		err := db.PingContext(ctx)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,102 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/gosukretess/battleships/internal/database"
+	"github.com/gosukretess/battleships/internal/database/dbtest"
+)
+
+func TestMain(m *testing.M) {
+	dbtest.Main(m)
+}
+
+func TestMigrateUpDown(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		db := dbtest.Open(t, backend)
+		migrator, err := database.NewMigrator(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		status, err := migrator.Status()
+		if err != nil {
+			t.Fatal(err)
+		}
+		total := len(status)
+		if total == 0 {
+			t.Fatal("no migrations found")
+		}
+
+		applied, err := migrator.Up()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if applied != total {
+			t.Fatalf("Up applied %d migrations, want %d", applied, total)
+		}
+		if applied, err := migrator.Up(); err != nil || applied != 0 {
+			t.Fatalf("second Up = %d, %v, want 0, nil", applied, err)
+		}
+		assertApplied(t, migrator, total)
+
+		rolledBack, err := migrator.Down(total)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rolledBack != total {
+			t.Fatalf("Down rolled back %d migrations, want %d", rolledBack, total)
+		}
+		assertApplied(t, migrator, 0)
+
+		// Every down script must leave a schema the up scripts apply to again.
+		if applied, err := migrator.Up(); err != nil || applied != total {
+			t.Fatalf("Up after Down = %d, %v, want %d, nil", applied, err, total)
+		}
+	})
+}
+
+func TestMigrateDownSteps(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		db := dbtest.New(t, backend)
+		migrator, err := database.NewMigrator(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		status, err := migrator.Status()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n, err := migrator.Down(1); err != nil || n != 1 {
+			t.Fatalf("Down(1) = %d, %v, want 1, nil", n, err)
+		}
+		status, err = migrator.Status()
+		if err != nil {
+			t.Fatal(err)
+		}
+		last := status[len(status)-1]
+		if last.Applied {
+			t.Errorf("migration %04d_%s is still applied", last.Version, last.Name)
+		}
+		assertApplied(t, migrator, len(status)-1)
+	})
+}
+
+func assertApplied(t *testing.T, migrator *database.Migrator, want int) {
+	t.Helper()
+
+	status, err := migrator.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied := 0
+	for _, s := range status {
+		if s.Applied {
+			applied++
+		}
+	}
+	if applied != want {
+		t.Errorf("%d migrations applied, want %d", applied, want)
+	}
+}
@@ -0,0 +1,9 @@
+ALTER TABLE moves
+    DROP CONSTRAINT moves_shot_key,
+    DROP CONSTRAINT moves_userid_fkey,
+    DROP CONSTRAINT moves_gameid_fkey,
+    ALTER COLUMN gameid DROP NOT NULL,
+    ALTER COLUMN userid DROP NOT NULL,
+    ALTER COLUMN x DROP NOT NULL,
+    ALTER COLUMN y DROP NOT NULL,
+    ALTER COLUMN hit DROP NOT NULL;
@@ -0,0 +1,18 @@
+-- Shots are unique per game, shooter and cell; duplicates left behind by the
+-- old check-then-insert logic are dropped, keeping the first one.
+DELETE FROM moves a USING moves b
+WHERE a.ctid > b.ctid
+  AND a.gameid = b.gameid AND a.userid = b.userid AND a.x = b.x AND a.y = b.y;
+
+DELETE FROM moves
+WHERE gameid NOT IN (SELECT id FROM games) OR userid NOT IN (SELECT id FROM users);
+
+ALTER TABLE moves
+    ALTER COLUMN gameid SET NOT NULL,
+    ALTER COLUMN userid SET NOT NULL,
+    ALTER COLUMN x SET NOT NULL,
+    ALTER COLUMN y SET NOT NULL,
+    ALTER COLUMN hit SET NOT NULL,
+    ADD CONSTRAINT moves_gameid_fkey FOREIGN KEY (gameid) REFERENCES games(id) ON DELETE CASCADE,
+    ADD CONSTRAINT moves_userid_fkey FOREIGN KEY (userid) REFERENCES users(id),
+    ADD CONSTRAINT moves_shot_key UNIQUE (gameid, userid, x, y);
@@ -0,0 +1 @@
+ALTER TABLE games ADD COLUMN rules TEXT NOT NULL DEFAULT 'draft';
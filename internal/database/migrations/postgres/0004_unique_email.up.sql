@@ -0,0 +1,4 @@
+-- Emails identify users, so they are stored normalized and must be unique.
+UPDATE users SET email = lower(trim(email)) WHERE email IS NOT NULL;
+
+CREATE UNIQUE INDEX users_email_key ON users (email);
@@ -0,0 +1,3 @@
+ALTER TABLE games DROP COLUMN finished;
+ALTER TABLE games DROP COLUMN winner;
+ALTER TABLE games DROP COLUMN status;
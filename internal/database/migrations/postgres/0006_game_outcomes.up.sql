@@ -0,0 +1,14 @@
+ALTER TABLE games ADD COLUMN status TEXT NOT NULL DEFAULT 'IN_PROGRESS';
+ALTER TABLE games ADD COLUMN winner TEXT;
+ALTER TABLE games ADD COLUMN finished TEXT;
+
+-- Games in which a player already hit every ship of the other one were won
+-- before outcomes were recorded. When they ended is not known.
+UPDATE games SET winner = (
+    SELECT m.userid FROM moves m
+    WHERE m.gameid = games.id AND m.hit
+    GROUP BY m.userid
+    HAVING count(*) >= (SELECT count(*) FROM ships s WHERE s.gameid = games.id AND s.userid <> m.userid)
+    LIMIT 1
+);
+UPDATE games SET status = 'FINISHED' WHERE winner IS NOT NULL;
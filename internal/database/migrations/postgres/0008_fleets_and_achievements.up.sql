@@ -0,0 +1,15 @@
+-- Cells of the same ship share its number. Ships placed before fleets had
+-- types are single cell boats without a number.
+ALTER TABLE ships ADD COLUMN ship INTEGER;
+ALTER TABLE ships ADD COLUMN type TEXT NOT NULL DEFAULT 'boat';
+
+-- The order of the shots in a game. Shots fired before it was recorded have none.
+ALTER TABLE moves ADD COLUMN seq INTEGER;
+
+CREATE TABLE achievements (
+    userid TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    achievement TEXT NOT NULL,
+    gameid TEXT,
+    unlocked TEXT NOT NULL,
+    PRIMARY KEY (userid, achievement)
+);
@@ -0,0 +1,10 @@
+-- A row is a friend request from userid to friendid until it is accepted.
+CREATE TABLE friends (
+    userid TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    friendid TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created TEXT NOT NULL,
+    accepted TEXT,
+    PRIMARY KEY (userid, friendid)
+);
+
+CREATE INDEX friends_friendid_idx ON friends (friendid);
@@ -0,0 +1,3 @@
+DROP INDEX games_status_created_idx;
+DROP INDEX games_userid2_created_idx;
+DROP INDEX games_userid1_created_idx;
@@ -0,0 +1,3 @@
+DROP INDEX games_nextuser_turnstarted_idx;
+ALTER TABLE games DROP COLUMN turnstarted;
+ALTER TABLE games DROP COLUMN mode;
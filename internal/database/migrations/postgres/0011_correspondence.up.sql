@@ -0,0 +1,8 @@
+ALTER TABLE games ADD COLUMN mode TEXT NOT NULL DEFAULT 'REALTIME';
+-- When the player in nextuser got the turn. Correspondence games are
+-- forfeited when a turn takes too long.
+ALTER TABLE games ADD COLUMN turnstarted TEXT;
+UPDATE games SET turnstarted = created;
+
+-- GetInbox lists the games waiting for a player, longest waiting first.
+CREATE INDEX games_nextuser_turnstarted_idx ON games (nextuser, turnstarted);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS moves;
+DROP TABLE IF EXISTS ships;
+DROP TABLE IF EXISTS games;
+DROP TABLE IF EXISTS users;
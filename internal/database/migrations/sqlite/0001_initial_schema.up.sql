@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS users (
+    id TEXT PRIMARY KEY,
+    name TEXT,
+    email TEXT
+);
+
+CREATE TABLE IF NOT EXISTS games (
+    id TEXT PRIMARY KEY,
+    userid1 TEXT,
+    userid2 TEXT,
+    created TEXT,
+    nextuser TEXT
+);
+
+CREATE TABLE IF NOT EXISTS ships (
+    gameid TEXT,
+    userid TEXT,
+    x INTEGER,
+    y INTEGER
+);
+
+CREATE TABLE IF NOT EXISTS moves (
+    gameid TEXT,
+    userid TEXT,
+    x INTEGER,
+    y INTEGER,
+    hit BOOLEAN
+);
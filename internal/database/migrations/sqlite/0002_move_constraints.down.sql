@@ -0,0 +1,13 @@
+CREATE TABLE moves_old (
+    gameid TEXT,
+    userid TEXT,
+    x INTEGER,
+    y INTEGER,
+    hit BOOLEAN
+);
+
+INSERT INTO moves_old (gameid, userid, x, y, hit)
+SELECT gameid, userid, x, y, hit FROM moves;
+
+DROP TABLE moves;
+ALTER TABLE moves_old RENAME TO moves;
@@ -0,0 +1,18 @@
+-- Shots are unique per game, shooter and cell; duplicates left behind by the
+-- old check-then-insert logic are dropped, keeping the first one.
+CREATE TABLE moves_new (
+    gameid TEXT NOT NULL REFERENCES games(id) ON DELETE CASCADE,
+    userid TEXT NOT NULL REFERENCES users(id),
+    x INTEGER NOT NULL,
+    y INTEGER NOT NULL,
+    hit BOOLEAN NOT NULL,
+    UNIQUE (gameid, userid, x, y)
+);
+
+INSERT OR IGNORE INTO moves_new (gameid, userid, x, y, hit)
+SELECT gameid, userid, x, y, hit FROM moves
+WHERE gameid IN (SELECT id FROM games) AND userid IN (SELECT id FROM users)
+ORDER BY rowid;
+
+DROP TABLE moves;
+ALTER TABLE moves_new RENAME TO moves;
@@ -0,0 +1,5 @@
+DROP TABLE sessions;
+
+ALTER TABLE users DROP COLUMN locked_until;
+ALTER TABLE users DROP COLUMN failed_logins;
+ALTER TABLE users DROP COLUMN password_hash;
@@ -0,0 +1,14 @@
+-- Existing users have no password until one is set with "server passwd".
+ALTER TABLE users ADD COLUMN password_hash TEXT;
+ALTER TABLE users ADD COLUMN failed_logins INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN locked_until TEXT;
+
+-- Only a hash of each session token is stored.
+CREATE TABLE sessions (
+    token_hash TEXT PRIMARY KEY,
+    userid TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created TEXT NOT NULL,
+    expires TEXT NOT NULL
+);
+
+CREATE INDEX sessions_userid_idx ON sessions (userid);
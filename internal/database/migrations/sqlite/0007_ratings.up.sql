@@ -0,0 +1,23 @@
+-- Elo ratings, kept separately for every rule set. Players without a row
+-- have the initial rating.
+CREATE TABLE ratings (
+    userid TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rules TEXT NOT NULL,
+    rating INTEGER NOT NULL,
+    games INTEGER NOT NULL,
+    PRIMARY KEY (userid, rules)
+);
+
+CREATE INDEX ratings_rules_rating_idx ON ratings (rules, rating);
+
+CREATE TABLE rating_history (
+    gameid TEXT NOT NULL REFERENCES games(id) ON DELETE CASCADE,
+    userid TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rules TEXT NOT NULL,
+    rating_before INTEGER NOT NULL,
+    rating_after INTEGER NOT NULL,
+    created TEXT NOT NULL,
+    PRIMARY KEY (gameid, userid)
+);
+
+CREATE INDEX rating_history_userid_idx ON rating_history (userid, created);
@@ -0,0 +1,6 @@
+DROP TABLE achievements;
+
+ALTER TABLE moves DROP COLUMN seq;
+
+ALTER TABLE ships DROP COLUMN type;
+ALTER TABLE ships DROP COLUMN ship;
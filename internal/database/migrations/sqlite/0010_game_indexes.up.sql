@@ -0,0 +1,4 @@
+-- ListGames filters by player and status and orders by creation time.
+CREATE INDEX games_userid1_created_idx ON games (userid1, created);
+CREATE INDEX games_userid2_created_idx ON games (userid2, created);
+CREATE INDEX games_status_created_idx ON games (status, created);
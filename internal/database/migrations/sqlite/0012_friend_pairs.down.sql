@@ -0,0 +1 @@
+DROP INDEX friends_pair_key;
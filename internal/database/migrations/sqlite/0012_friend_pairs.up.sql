@@ -0,0 +1,13 @@
+-- Two users asking each other at the same time could both insert a request.
+-- Such pairs become friends, as both asked, and one of the two rows is dropped.
+UPDATE friends SET accepted = created
+WHERE accepted IS NULL AND EXISTS (
+    SELECT 1 FROM friends f WHERE f.userid = friends.friendid AND f.friendid = friends.userid
+);
+DELETE FROM friends
+WHERE userid > friendid AND EXISTS (
+    SELECT 1 FROM friends f WHERE f.userid = friends.friendid AND f.friendid = friends.userid
+);
+
+-- At most one row per pair of users, whoever asked.
+CREATE UNIQUE INDEX friends_pair_key ON friends (min(userid, friendid), max(userid, friendid));
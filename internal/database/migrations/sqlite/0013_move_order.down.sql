@@ -0,0 +1 @@
+DROP INDEX moves_seq_key;
@@ -0,0 +1,9 @@
+-- The order of the shots is unique within a game. Move numbers a shot while
+-- it holds the turn, so duplicates are not expected; any left over lose their
+-- number, except for the first shot.
+UPDATE moves SET seq = NULL
+WHERE seq IS NOT NULL AND EXISTS (
+    SELECT 1 FROM moves m WHERE m.gameid = moves.gameid AND m.seq = moves.seq AND m.rowid < moves.rowid
+);
+
+CREATE UNIQUE INDEX moves_seq_key ON moves (gameid, seq);
@@ -0,0 +1,151 @@
+package game
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gosukretess/battleships/internal/achievement"
+)
+
+// AchievementDto is an achievement unlocked by a user.
+type AchievementDto struct {
+	Id       string
+	GameId   string
+	Unlocked time.Time
+}
+
+// CheckAchievements unlocks what userId has earned in game with their last
+// shot, described by result, or by winning the game any other way. It
+// returns only the achievements the user did not have before.
+func (s *Store) CheckAchievements(ctx context.Context, game GameDto, userId string, result MoveResult, won bool) ([]AchievementDto, error) {
+	ctx, done := s.instrument(ctx, "check_achievements")
+	defer done()
+
+	var earned []string
+	if won {
+		earned = append(earned, achievement.FirstWin)
+		streak, err := s.winStreak(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+		if streak {
+			earned = append(earned, achievement.WinStreak)
+		}
+	}
+
+	rules, _ := LookupRules(game.Rules)
+	if result.Won {
+		shots, steady, err := s.steadyShots(ctx, game.Id, userId)
+		if err != nil {
+			return nil, err
+		}
+		if steady {
+			earned = append(earned, achievement.Sharpshooter)
+		}
+		if rules.QuickWin > 0 && shots <= rules.QuickWin {
+			earned = append(earned, achievement.QuickWin)
+		}
+	}
+	if result.Sunk == Carrier {
+		strike, err := s.sunkInARow(ctx, game.Id, userId, rules.shipSize(Carrier))
+		if err != nil {
+			return nil, err
+		}
+		if strike {
+			earned = append(earned, achievement.CarrierStrike)
+		}
+	}
+
+	var unlocked []AchievementDto
+	for _, id := range earned {
+		a := AchievementDto{Id: id, GameId: game.Id, Unlocked: time.Now().UTC()}
+		res, err := s.db.ExecContext(ctx, `
+			INSERT INTO achievements (userid, achievement, gameid, unlocked) VALUES (?, ?, ?, ?)
+			ON CONFLICT DO NOTHING`, userId, a.Id, a.GameId, a.Unlocked.Format(historyTimeFormat))
+		if err != nil {
+			return unlocked, err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return unlocked, err
+		} else if n > 0 {
+			unlocked = append(unlocked, a)
+		}
+	}
+	return unlocked, nil
+}
+
+// winStreak tells whether the last achievement.StreakLength finished games of
+// a user were all won.
+func (s *Store) winStreak(ctx context.Context, userId string) (bool, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT winner FROM games
+		WHERE (userid1 = ? OR userid2 = ?) AND status <> ?
+		ORDER BY COALESCE(finished, created) DESC LIMIT ?`,
+		userId, userId, StatusInProgress, achievement.StreakLength)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	games := 0
+	for rows.Next() {
+		var winner sql.NullString
+		if err := rows.Scan(&winner); err != nil {
+			return false, err
+		}
+		if winner.String != userId {
+			return false, nil
+		}
+		games++
+	}
+	return games == achievement.StreakLength, rows.Err()
+}
+
+// steadyShots counts the shots of a user in a game and tells whether they
+// never missed achievement.MissesInARow times in a row. Games with shots
+// fired before their order was recorded are never steady.
+func (s *Store) steadyShots(ctx context.Context, gameId, userId string) (int, bool, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT hit, seq FROM moves WHERE gameid = ? AND userid = ? ORDER BY seq",
+		gameId, userId)
+	if err != nil {
+		return 0, false, err
+	}
+	defer rows.Close()
+
+	shots, misses, steady := 0, 0, true
+	for rows.Next() {
+		var hit bool
+		var seq sql.NullInt64
+		if err := rows.Scan(&hit, &seq); err != nil {
+			return 0, false, err
+		}
+		shots++
+		if !seq.Valid {
+			steady = false
+		}
+		if hit {
+			misses = 0
+		} else if misses++; misses >= achievement.MissesInARow {
+			steady = false
+		}
+	}
+	return shots, steady, rows.Err()
+}
+
+// sunkInARow tells whether the last size shots of a user all hit the same
+// ship, which only happens when it was sunk without a miss in between.
+func (s *Store) sunkInARow(ctx context.Context, gameId, userId string, size int) (bool, error) {
+	if size == 0 {
+		return false, nil
+	}
+	var cells, ships int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT count(*), count(DISTINCT s.ship) FROM (
+			SELECT x, y FROM moves WHERE gameid = ? AND userid = ? AND seq IS NOT NULL
+			ORDER BY seq DESC LIMIT ?
+		) m
+		JOIN ships s ON s.gameid = ? AND s.userid <> ? AND s.x = m.x AND s.y = m.y`,
+		gameId, userId, size, gameId, userId).Scan(&cells, &ships)
+	return cells == size && ships == 1, err
+}
@@ -0,0 +1,51 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/gosukretess/battleships/proto/gamepb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo details of gameErrors.
+const errorDomain = "battleships"
+
+var errorStatusCodes = map[gamepb.ErrorCode]codes.Code{
+	gamepb.ErrorCode_OUT_OF_BOUNDS:  codes.InvalidArgument,
+	gamepb.ErrorCode_NOT_YOUR_TURN:  codes.FailedPrecondition,
+	gamepb.ErrorCode_GAME_NOT_FOUND: codes.NotFound,
+	gamepb.ErrorCode_GAME_FINISHED:  codes.FailedPrecondition,
+	gamepb.ErrorCode_INTERNAL:       codes.Internal,
+	gamepb.ErrorCode_NOT_A_PLAYER:   codes.PermissionDenied,
+	gamepb.ErrorCode_RATE_LIMITED:   codes.ResourceExhausted,
+}
+
+// gameError is why a player's shot or subscription was rejected. On a stream
+// it becomes an ERROR event, returned from an RPC a status with the code as
+// the reason of an ErrorInfo detail, so that bots do not have to parse the
+// message.
+type gameError struct {
+	code    gamepb.ErrorCode
+	message string
+}
+
+func newGameError(code gamepb.ErrorCode, format string, args ...any) *gameError {
+	return &gameError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+func (e *gameError) Error() string {
+	return e.message
+}
+
+func (e *gameError) GRPCStatus() *status.Status {
+	st := status.New(errorStatusCodes[e.code], e.message)
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: e.code.String(), Domain: errorDomain}); err == nil {
+		st = detailed
+	}
+	return st
+}
+
+// errInternal hides failures of the server from players.
+var errInternal = newGameError(gamepb.ErrorCode_INTERNAL, "internal error, try again")
@@ -0,0 +1,290 @@
+package game
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gosukretess/battleships/internal/presence"
+	"github.com/gosukretess/battleships/proto/gamepb"
+)
+
+// Hub routes game events to the players connected to a game, no matter
+// whether they are connected over a gRPC stream or a WebSocket. It keeps the
+// presence of the players up to date as they connect to games.
+type Hub struct {
+	mu       sync.Mutex
+	presence *presence.Tracker
+	conns    map[*Conn]struct{}
+	games    map[string]map[*Conn]struct{}
+}
+
+const (
+	// sendQueue is how many events a connection may fall behind before it is
+	// dropped.
+	sendQueue = 64
+	// flushTimeout bounds how long Close waits for the queued events of a
+	// connection to be written.
+	flushTimeout = 5 * time.Second
+)
+
+// ErrSlowConn is the error of a connection dropped because it did not keep
+// up with its events.
+var ErrSlowConn = errors.New("connection fell behind")
+
+var errConnClosed = errors.New("connection closed")
+
+// Conn is one connected player. Events for every game it subscribed to are
+// queued and passed to its send function, one at a time, by a goroutine of
+// the connection, so that a slow player never holds up the others. A
+// connection whose queue fills up, or whose send function fails, is done.
+type Conn struct {
+	hub    *Hub
+	userId string
+	send   func(*gamepb.GameEvent) error
+	// sendMu guards out, closed and err.
+	sendMu sync.Mutex
+	out    chan *gamepb.GameEvent
+	closed bool
+	err    error
+	// failed is closed when the connection fails, written when its writer
+	// returns.
+	failed  chan struct{}
+	written chan struct{}
+	// games is guarded by hub.mu.
+	games map[string]struct{}
+}
+
+func NewHub(tracker *presence.Tracker) *Hub {
+	return &Hub{
+		presence: tracker,
+		conns:    make(map[*Conn]struct{}),
+		games:    make(map[string]map[*Conn]struct{}),
+	}
+}
+
+// Connect registers a connection of userId. It receives broadcasts right away
+// and the events of a game once it subscribes to it.
+func (h *Hub) Connect(userId string, send func(*gamepb.GameEvent) error) *Conn {
+	conn := &Conn{
+		hub:     h,
+		userId:  userId,
+		send:    send,
+		out:     make(chan *gamepb.GameEvent, sendQueue),
+		failed:  make(chan struct{}),
+		written: make(chan struct{}),
+		games:   make(map[string]struct{}),
+	}
+	go conn.write()
+
+	h.mu.Lock()
+	h.conns[conn] = struct{}{}
+	h.mu.Unlock()
+	h.presence.Connect(userId)
+	return conn
+}
+
+// Publish sends event to every connection subscribed to gameId.
+func (h *Hub) Publish(gameId string, event *gamepb.GameEvent) {
+	h.PublishTo(gameId, "", event)
+}
+
+// PublishTo sends event to the connections of userId subscribed to gameId, or
+// to all of them if userId is empty.
+func (h *Hub) PublishTo(gameId, userId string, event *gamepb.GameEvent) {
+	h.mu.Lock()
+	conns := make([]*Conn, 0, len(h.games[gameId]))
+	for conn := range h.games[gameId] {
+		if userId == "" || conn.userId == userId {
+			conns = append(conns, conn)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.Send(event)
+	}
+}
+
+// Watching tells whether userId has a connection subscribed to gameId.
+func (h *Hub) Watching(gameId, userId string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for conn := range h.games[gameId] {
+		if conn.userId == userId {
+			return true
+		}
+	}
+	return false
+}
+
+// Finish unsubscribes every connection from a game that is over.
+func (h *Hub) Finish(gameId string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for conn := range h.games[gameId] {
+		delete(conn.games, gameId)
+		h.presence.LeaveGame(conn.userId, gameId)
+	}
+	delete(h.games, gameId)
+}
+
+// Broadcast sends event to every connection, subscribed to a game or not.
+func (h *Hub) Broadcast(event *gamepb.GameEvent) {
+	h.mu.Lock()
+	conns := make([]*Conn, 0, len(h.conns))
+	for conn := range h.conns {
+		conns = append(conns, conn)
+	}
+	h.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.Send(event)
+	}
+}
+
+func (c *Conn) Subscribe(gameId string) {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+
+	if _, ok := c.hub.conns[c]; !ok {
+		return
+	}
+	if _, ok := c.games[gameId]; ok {
+		return
+	}
+	if c.hub.games[gameId] == nil {
+		c.hub.games[gameId] = make(map[*Conn]struct{})
+	}
+	c.hub.games[gameId][c] = struct{}{}
+	c.games[gameId] = struct{}{}
+	c.hub.presence.EnterGame(c.userId, gameId)
+}
+
+// Unsubscribe stops sending the events of a game to the connection.
+func (c *Conn) Unsubscribe(gameId string) {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+
+	if _, ok := c.games[gameId]; !ok {
+		return
+	}
+	delete(c.games, gameId)
+	delete(c.hub.games[gameId], c)
+	if len(c.hub.games[gameId]) == 0 {
+		delete(c.hub.games, gameId)
+	}
+	c.hub.presence.LeaveGame(c.userId, gameId)
+}
+
+func (c *Conn) Subscribed(gameId string) bool {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+	_, ok := c.games[gameId]
+	return ok
+}
+
+// Send queues an event for this connection only. It never blocks: if the
+// queue is full the connection is dropped with ErrSlowConn.
+func (c *Conn) Send(event *gamepb.GameEvent) error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+	if c.closed {
+		return errConnClosed
+	}
+	select {
+	case c.out <- event:
+		return nil
+	default:
+		c.failLocked(ErrSlowConn)
+		return ErrSlowConn
+	}
+}
+
+// Done is closed when the connection fails. Its handler should then close
+// the stream or socket, and the connection.
+func (c *Conn) Done() <-chan struct{} {
+	return c.failed
+}
+
+// Err tells why the connection failed, or returns nil.
+func (c *Conn) Err() error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	return c.err
+}
+
+func (c *Conn) write() {
+	defer close(c.written)
+	for {
+		select {
+		case <-c.failed:
+			return
+		case event, ok := <-c.out:
+			if !ok {
+				return
+			}
+			if err := c.send(event); err != nil {
+				c.fail(err)
+				return
+			}
+		}
+	}
+}
+
+func (c *Conn) fail(err error) {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	c.failLocked(err)
+}
+
+func (c *Conn) failLocked(err error) {
+	if c.err == nil {
+		c.err = err
+		close(c.failed)
+	}
+}
+
+// Close unsubscribes the connection from all its games and waits a while for
+// the events already queued to be written.
+func (c *Conn) Close() {
+	c.unregister()
+
+	c.sendMu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.out)
+	}
+	c.sendMu.Unlock()
+
+	select {
+	case <-c.written:
+	case <-time.After(flushTimeout):
+		c.fail(errConnClosed)
+	}
+}
+
+func (c *Conn) unregister() {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+
+	if _, ok := c.hub.conns[c]; !ok {
+		return
+	}
+	for gameId := range c.games {
+		delete(c.hub.games[gameId], c)
+		if len(c.hub.games[gameId]) == 0 {
+			delete(c.hub.games, gameId)
+		}
+		c.hub.presence.LeaveGame(c.userId, gameId)
+	}
+	c.games = nil
+	delete(c.hub.conns, c)
+	c.hub.presence.Disconnect(c.userId)
+}
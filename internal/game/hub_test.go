@@ -0,0 +1,174 @@
+package game
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gosukretess/battleships/internal/presence"
+	"github.com/gosukretess/battleships/proto/gamepb"
+)
+
+func TestHubSlowConnDropped(t *testing.T) {
+	hub := NewHub(presence.NewTracker())
+	release := make(chan struct{})
+	slow := hub.Connect(alice, func(*gamepb.GameEvent) error {
+		<-release
+		return nil
+	})
+	defer func() {
+		close(release)
+		slow.Close()
+	}()
+	fast := make(chan *gamepb.GameEvent, 1)
+	conn := hub.Connect(bob, func(event *gamepb.GameEvent) error {
+		fast <- event
+		return nil
+	})
+	defer conn.Close()
+	slow.Subscribe("g1")
+	conn.Subscribe("g1")
+
+	// The writer of the slow connection holds one event, the queue the rest.
+	// Bob keeps up all along.
+	for i := range sendQueue + 2 {
+		hub.Publish("g1", &gamepb.GameEvent{Type: gamepb.EventType_MISS, GameId: "g1"})
+		select {
+		case <-fast:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("bob did not receive event %d", i)
+		}
+	}
+
+	select {
+	case <-slow.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow connection was not dropped")
+	}
+	if !errors.Is(slow.Err(), ErrSlowConn) {
+		t.Errorf("slow connection failed with %v, want ErrSlowConn", slow.Err())
+	}
+	if err := slow.Send(&gamepb.GameEvent{}); !errors.Is(err, ErrSlowConn) {
+		t.Errorf("Send on a dropped connection: %v", err)
+	}
+	if conn.Err() != nil {
+		t.Errorf("bob's connection failed with %v", conn.Err())
+	}
+}
+
+func TestConnCloseFlushes(t *testing.T) {
+	hub := NewHub(presence.NewTracker())
+	var sent []*gamepb.GameEvent
+	conn := hub.Connect(alice, func(event *gamepb.GameEvent) error {
+		time.Sleep(time.Millisecond)
+		sent = append(sent, event)
+		return nil
+	})
+	for range 10 {
+		conn.Send(&gamepb.GameEvent{Type: gamepb.EventType_SERVER_SHUTDOWN})
+	}
+	conn.Close()
+
+	if len(sent) != 10 {
+		t.Errorf("%d events written before Close returned, want 10", len(sent))
+	}
+	if err := conn.Send(&gamepb.GameEvent{}); err == nil {
+		t.Error("Send on a closed connection succeeded")
+	}
+}
+
+// hubConn connects userId to hub and returns the events it receives.
+func hubConn(t *testing.T, hub *Hub, userId string) (*Conn, <-chan *gamepb.GameEvent) {
+	t.Helper()
+
+	events := make(chan *gamepb.GameEvent, sendQueue)
+	conn := hub.Connect(userId, func(event *gamepb.GameEvent) error {
+		events <- event
+		return nil
+	})
+	t.Cleanup(conn.Close)
+	return conn, events
+}
+
+// received returns, for every connection, the games of the events it
+// received before a broadcast that marks the end.
+func received(t *testing.T, hub *Hub, conns ...<-chan *gamepb.GameEvent) [][]string {
+	t.Helper()
+
+	hub.Broadcast(&gamepb.GameEvent{Type: gamepb.EventType_SERVER_SHUTDOWN})
+	games := make([][]string, len(conns))
+	for i, events := range conns {
+	receive:
+		for {
+			select {
+			case event := <-events:
+				if event.Type == gamepb.EventType_SERVER_SHUTDOWN {
+					break receive
+				}
+				games[i] = append(games[i], event.GameId)
+			case <-time.After(5 * time.Second):
+				t.Fatal("broadcast not received")
+			}
+		}
+	}
+	return games
+}
+
+func TestHubRouting(t *testing.T) {
+	tracker := presence.NewTracker()
+	hub := NewHub(tracker)
+	aliceConn, aliceEvents := hubConn(t, hub, alice)
+	bobConn, bobEvents := hubConn(t, hub, bob)
+	_, carolEvents := hubConn(t, hub, "carol")
+	aliceConn.Subscribe("g1")
+	aliceConn.Subscribe("g2")
+	aliceConn.Subscribe("g2")
+	bobConn.Subscribe("g1")
+
+	publish := func(gameId string) {
+		hub.Publish(gameId, &gamepb.GameEvent{Type: gamepb.EventType_MISS, GameId: gameId})
+	}
+	steps := []struct {
+		name              string
+		act               func()
+		alice, bob, carol []string
+	}{
+		{name: "publish", act: func() { publish("g1"); publish("g2"); publish("g3") },
+			alice: []string{"g1", "g2"}, bob: []string{"g1"}},
+		{name: "publish to bob", act: func() {
+			hub.PublishTo("g1", bob, &gamepb.GameEvent{Type: gamepb.EventType_ACHIEVEMENT, GameId: "g1"})
+		}, bob: []string{"g1"}},
+		{name: "unsubscribe", act: func() { aliceConn.Unsubscribe("g1"); publish("g1"); publish("g2") },
+			alice: []string{"g2"}, bob: []string{"g1"}},
+		{name: "finish", act: func() { hub.Finish("g2"); publish("g2"); publish("g1") },
+			bob: []string{"g1"}},
+		{name: "subscribe again", act: func() { aliceConn.Subscribe("g2"); publish("g2") },
+			alice: []string{"g2"}},
+	}
+	for _, step := range steps {
+		step.act()
+		got := received(t, hub, aliceEvents, bobEvents, carolEvents)
+		for i, want := range [][]string{step.alice, step.bob, step.carol} {
+			if !slices.Equal(got[i], want) {
+				t.Errorf("%s: %s received the events of %v, want %v", step.name, []string{alice, bob, "carol"}[i], got[i], want)
+			}
+		}
+	}
+
+	if !hub.Watching("g2", alice) || hub.Watching("g1", alice) || !hub.Watching("g1", bob) {
+		t.Error("Watching does not match the subscriptions")
+	}
+	if got := tracker.Status(alice); got != presence.InGame {
+		t.Errorf("alice is %s, want %s", got, presence.InGame)
+	}
+	aliceConn.Unsubscribe("g2")
+	if got := tracker.Status(alice); got != presence.Idle {
+		t.Errorf("alice after leaving her games is %s, want %s", got, presence.Idle)
+	}
+	bobConn.Close()
+	bobConn.Subscribe("g3")
+	if got := tracker.Status(bob); got != presence.Offline || hub.Watching("g3", bob) {
+		t.Errorf("bob after closing is %s, watching g3 %t", got, hub.Watching("g3", bob))
+	}
+}
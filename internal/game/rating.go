@@ -0,0 +1,228 @@
+package game
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gosukretess/battleships/internal/database"
+)
+
+const (
+	// InitialRating is the Elo rating of a player who has not finished a
+	// game in a rule set yet.
+	InitialRating = 1500
+	// kFactor is the most a rating can change in one game.
+	kFactor = 32
+
+	defaultPageSize = 20
+	maxPageSize     = 100
+
+	// historyTimeFormat is RFC 3339 with fixed width fractions, so that the
+	// order of the text is the order of the changes.
+	historyTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// eloChange returns how many points the winner takes from the loser.
+func eloChange(winner, loser int) int {
+	expected := 1 / (1 + math.Pow(10, float64(loser-winner)/400))
+	return int(math.Round(kFactor * (1 - expected)))
+}
+
+// updateRatings applies the result of a game that has just ended to the
+// ratings of both players in the rule set of the game and records the change.
+func (s *Store) updateRatings(ctx context.Context, tx *database.Tx, gameId, winner string) error {
+	var userId1, userId2, rules string
+	err := tx.QueryRowContext(ctx, "SELECT userid1, userid2, rules FROM games WHERE id = ?", gameId).Scan(&userId1, &userId2, &rules)
+	if err != nil {
+		return err
+	}
+	loser := userId1
+	if loser == winner {
+		loser = userId2
+	}
+	if loser == winner {
+		// Nothing to learn from a game against yourself.
+		return nil
+	}
+
+	// Both rows are locked, in a fixed order, until the transaction ends, so
+	// that a game of either player ending at the same time waits for this
+	// one and works from the updated rating.
+	ratings := map[string]int{}
+	for _, userId := range []string{min(winner, loser), max(winner, loser)} {
+		if ratings[userId], err = s.lockRating(ctx, tx, userId, rules); err != nil {
+			return err
+		}
+	}
+	winnerRating, loserRating := ratings[winner], ratings[loser]
+
+	change := eloChange(winnerRating, loserRating)
+	now := time.Now().UTC().Format(historyTimeFormat)
+	for _, r := range []struct {
+		userId        string
+		before, after int
+	}{
+		{winner, winnerRating, winnerRating + change},
+		{loser, loserRating, loserRating - change},
+	} {
+		_, err := tx.ExecContext(ctx, "UPDATE ratings SET rating = ?, games = games + 1 WHERE userid = ? AND rules = ?",
+			r.after, r.userId, rules)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "INSERT INTO rating_history (gameid, userid, rules, rating_before, rating_after, created) VALUES (?, ?, ?, ?, ?, ?)",
+			gameId, r.userId, rules, r.before, r.after, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockRating returns the rating of a user in a rule set and locks its row
+// for the rest of tx. A player without one gets a row with InitialRating and
+// no games. The no-op update is what takes the lock, and waits for another
+// transaction holding it, on PostgreSQL. SQLite transactions hold the write
+// lock of the whole database anyway.
+func (s *Store) lockRating(ctx context.Context, tx *database.Tx, userId, rules string) (int, error) {
+	var rating int
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO ratings (userid, rules, rating, games) VALUES (?, ?, ?, 0)
+		ON CONFLICT (userid, rules) DO UPDATE SET games = ratings.games
+		RETURNING rating`,
+		userId, rules, InitialRating).Scan(&rating)
+	return rating, err
+}
+
+// GetLeaderboard returns one page of ratings, best first. An empty rules
+// covers all rule sets. The second result is the offset of the next page, or
+// 0 on the last page.
+func (s *Store) GetLeaderboard(ctx context.Context, rules string, offset, limit int) ([]RatingDto, int, error) {
+	ctx, done := s.instrument(ctx, "get_leaderboard")
+	defer done()
+
+	query := "SELECT r.userid, u.name, r.rules, r.rating, r.games FROM ratings r JOIN users u ON u.id = r.userid"
+	args := []any{}
+	if rules != "" {
+		query += " WHERE r.rules = ?"
+		args = append(args, rules)
+	}
+	// One more row than asked for tells whether there is a next page.
+	query += " ORDER BY r.rating DESC, r.games DESC, r.userid LIMIT ? OFFSET ?"
+	args = append(args, limit+1, offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var ratings []RatingDto
+	for rows.Next() {
+		var r RatingDto
+		if err := rows.Scan(&r.UserId, &r.Name, &r.Rules, &r.Rating, &r.Games); err != nil {
+			return nil, 0, err
+		}
+		ratings = append(ratings, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if len(ratings) > limit {
+		return ratings[:limit], offset + limit, nil
+	}
+	return ratings, 0, nil
+}
+
+// GetRatingHistory returns the rating changes of a user, oldest first.
+func (s *Store) GetRatingHistory(ctx context.Context, userId, rules string) ([]RatingChangeDto, error) {
+	ctx, done := s.instrument(ctx, "get_rating_history")
+	defer done()
+
+	query := `
+		SELECT h.gameid, h.rules, CASE WHEN g.userid1 = h.userid THEN g.userid2 ELSE g.userid1 END,
+			g.winner = h.userid, h.rating_before, h.rating_after, h.created
+		FROM rating_history h JOIN games g ON g.id = h.gameid
+		WHERE h.userid = ?`
+	args := []any{userId}
+	if rules != "" {
+		query += " AND h.rules = ?"
+		args = append(args, rules)
+	}
+	query += " ORDER BY h.created, h.gameid"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []RatingChangeDto
+	for rows.Next() {
+		var c RatingChangeDto
+		if err := rows.Scan(&c.GameId, &c.Rules, &c.OpponentId, &c.Won, &c.RatingBefore, &c.RatingAfter, &c.Created); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// Page tokens are opaque to clients, so the paging scheme can change later.
+func encodePageToken(offset int) string {
+	if offset == 0 {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+	offset, err := strconv.Atoi(string(b))
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidPageToken
+	}
+	return offset, nil
+}
+
+// pageSize applies the default and the maximum to a requested page size.
+func pageSize(requested int32) int {
+	switch {
+	case requested <= 0:
+		return defaultPageSize
+	case requested > maxPageSize:
+		return maxPageSize
+	}
+	return int(requested)
+}
+
+type RatingDto struct {
+	UserId string
+	Name   string
+	Rules  string
+	Rating int
+	Games  int
+}
+
+type RatingChangeDto struct {
+	GameId       string
+	Rules        string
+	OpponentId   string
+	Won          bool
+	RatingBefore int
+	RatingAfter  int
+	Created      string
+}
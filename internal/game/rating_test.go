@@ -0,0 +1,171 @@
+package game
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/gosukretess/battleships/internal/database/dbtest"
+	"github.com/gosukretess/battleships/proto/gamepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEloChange(t *testing.T) {
+	tests := []struct {
+		winner, loser, want int
+	}{
+		{winner: 1500, loser: 1500, want: 16},
+		{winner: 1700, loser: 1500, want: 8},
+		{winner: 1500, loser: 1700, want: 24},
+		{winner: 2400, loser: 1000, want: 0},
+		{winner: 1000, loser: 2400, want: 32},
+	}
+	for _, tt := range tests {
+		if got := eloChange(tt.winner, tt.loser); got != tt.want {
+			t.Errorf("eloChange(%d, %d) = %d, want %d", tt.winner, tt.loser, got, tt.want)
+		}
+	}
+}
+
+func TestPageToken(t *testing.T) {
+	for _, offset := range []int{0, 1, 20, 12345} {
+		got, err := decodePageToken(encodePageToken(offset))
+		if err != nil || got != offset {
+			t.Errorf("offset %d decoded as %d, %v", offset, got, err)
+		}
+	}
+	for _, token := range []string{"!!", base64.RawURLEncoding.EncodeToString([]byte("ten")), base64.RawURLEncoding.EncodeToString([]byte("-5"))} {
+		if _, err := decodePageToken(token); err != ErrInvalidPageToken {
+			t.Errorf("token %q: error %v, want %v", token, err, ErrInvalidPageToken)
+		}
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		requested int32
+		want      int
+	}{
+		{requested: -1, want: defaultPageSize},
+		{requested: 0, want: defaultPageSize},
+		{requested: 7, want: 7},
+		{requested: maxPageSize + 1, want: maxPageSize},
+	}
+	for _, tt := range tests {
+		if got := pageSize(tt.requested); got != tt.want {
+			t.Errorf("pageSize(%d) = %d, want %d", tt.requested, got, tt.want)
+		}
+	}
+}
+
+// playRated ends a game between winner and loser by resignation of the loser.
+func playRated(t *testing.T, s *Server, winner, loser string) GameDto {
+	t.Helper()
+
+	ctx := context.Background()
+	g, err := s.store.CreateGame(ctx, winner, loser, DefaultRules, ModeRealtime, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.store.Resign(ctx, g.Id, winner); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestGetLeaderboard(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		s := newTestServer(t, backend)
+		for _, id := range []string{"dave", "erin"} {
+			if _, err := s.store.db.Exec("INSERT INTO users(id, name, email) VALUES (?, ?, ?)", id, id, id+"@example.com"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, game := range [][2]string{{alice, bob}, {alice, carol}, {bob, carol}, {"dave", "erin"}, {alice, "dave"}} {
+			playRated(t, s, game[0], game[1])
+		}
+
+		var entries []*gamepb.LeaderboardEntry
+		token := ""
+		for pages := 1; ; pages++ {
+			resp, err := s.GetLeaderboard(context.Background(), &gamepb.GetLeaderboardRequest{PageSize: 2, PageToken: token})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Entries) > 2 {
+				t.Errorf("page %d has %d entries", pages, len(resp.Entries))
+			}
+			entries = append(entries, resp.Entries...)
+			token = resp.NextPageToken
+			if token == "" {
+				break
+			}
+			if pages > 5 {
+				t.Fatal("leaderboard does not end")
+			}
+		}
+
+		if len(entries) != 5 {
+			t.Fatalf("leaderboard has %d entries, want 5", len(entries))
+		}
+		seen := map[string]bool{}
+		for i, e := range entries {
+			if e.Rank != int32(i+1) {
+				t.Errorf("entry %d has rank %d", i, e.Rank)
+			}
+			if i > 0 && e.Rating > entries[i-1].Rating {
+				t.Errorf("%s (%d) ranked below %s (%d)", e.UserId, e.Rating, entries[i-1].UserId, entries[i-1].Rating)
+			}
+			if seen[e.UserId] {
+				t.Errorf("%s is on the leaderboard twice", e.UserId)
+			}
+			seen[e.UserId] = true
+		}
+		if entries[0].UserId != alice || entries[0].Games != 3 {
+			t.Errorf("leader is %+v, want alice with 3 games", entries[0])
+		}
+
+		for _, req := range []*gamepb.GetLeaderboardRequest{
+			{PageToken: "!!"},
+			{Rules: "no-such-rules"},
+		} {
+			if _, err := s.GetLeaderboard(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("GetLeaderboard(%v): error %v, want InvalidArgument", req, err)
+			}
+		}
+	})
+}
+
+func TestGetRatingHistory(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		s := newTestServer(t, backend)
+		games := []GameDto{
+			playRated(t, s, alice, bob),
+			playRated(t, s, carol, alice),
+			playRated(t, s, alice, bob),
+		}
+
+		resp, err := s.GetRatingHistory(context.Background(), &gamepb.GetRatingHistoryRequest{UserId: alice})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []struct {
+			opponent string
+			won      bool
+		}{{bob, true}, {carol, false}, {bob, true}}
+		if len(resp.Changes) != len(want) {
+			t.Fatalf("alice has %d rating changes, want %d", len(resp.Changes), len(want))
+		}
+		rating := int32(InitialRating)
+		for i, c := range resp.Changes {
+			if c.GameId != games[i].Id || c.OpponentId != want[i].opponent || c.Won != want[i].won {
+				t.Errorf("change %d = %+v, want game %s against %s, won %t", i, c, games[i].Id, want[i].opponent, want[i].won)
+			}
+			if c.RatingBefore != rating || (c.RatingAfter > c.RatingBefore) != c.Won {
+				t.Errorf("change %d from %d to %d, want to start at %d", i, c.RatingBefore, c.RatingAfter, rating)
+			}
+			rating = c.RatingAfter
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package game
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/gosukretess/battleships/proto/gamepb"
+)
+
+const DefaultRules = "draft"
+
+// Ship types. Ships placed before ships had types are single cell boats.
+const (
+	Boat       = "boat"
+	Destroyer  = "destroyer"
+	Submarine  = "submarine"
+	Cruiser    = "cruiser"
+	Battleship = "battleship"
+	Carrier    = "carrier"
+)
+
+// ShipSpec is one kind of ship in a fleet.
+type ShipSpec struct {
+	Type  string
+	Size  int
+	Count int
+}
+
+type Rules struct {
+	Name      string
+	BoardSize int
+	Fleet     []ShipSpec
+	// QuickWin is the number of shots a player must win in to unlock the
+	// QUICK_WIN achievement.
+	QuickWin int
+}
+
+var ruleSets = map[string]Rules{
+	// The draft board has twelve single cell boats.
+	"draft": {
+		Name:      "draft",
+		BoardSize: 8,
+		Fleet:     []ShipSpec{{Type: Boat, Size: 1, Count: 12}},
+		QuickWin:  30,
+	},
+	"classic": {
+		Name:      "classic",
+		BoardSize: 10,
+		Fleet: []ShipSpec{
+			{Type: Carrier, Size: 5, Count: 1},
+			{Type: Battleship, Size: 4, Count: 1},
+			{Type: Cruiser, Size: 3, Count: 1},
+			{Type: Submarine, Size: 3, Count: 1},
+			{Type: Destroyer, Size: 2, Count: 1},
+		},
+		QuickWin: 40,
+	},
+}
+
+// DefaultTurnTimeout is how long a move in a correspondence game may take
+// unless configured otherwise.
+const DefaultTurnTimeout = 72 * time.Hour
+
+type Config struct {
+	DefaultRules string `yaml:"default_rules" toml:"default_rules"`
+	// TurnTimeout is how long a player in a correspondence game has to move
+	// before they lose the game.
+	TurnTimeout time.Duration `yaml:"turn_timeout" toml:"turn_timeout"`
+}
+
+func LookupRules(name string) (Rules, bool) {
+	rules, ok := ruleSets[name]
+	return rules, ok
+}
+
+func RuleSetNames() []string {
+	names := make([]string, 0, len(ruleSets))
+	for name := range ruleSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Ships is the number of ships in the fleet.
+func (r Rules) Ships() int {
+	ships := 0
+	for _, spec := range r.Fleet {
+		ships += spec.Count
+	}
+	return ships
+}
+
+// shipSize is the size of ships of a type, or 0 if the fleet has none.
+func (r Rules) shipSize(shipType string) int {
+	for _, spec := range r.Fleet {
+		if spec.Type == shipType {
+			return spec.Size
+		}
+	}
+	return 0
+}
+
+// shipCell is one cell of a ship on the board. All cells of a ship share
+// its number.
+type shipCell struct {
+	Ship int
+	Type string
+	X    int
+	Y    int
+}
+
+// placeFleet puts the fleet on the board at random, horizontally or
+// vertically, without overlapping ships.
+func (r Rules) placeFleet() []shipCell {
+	taken := make(map[[2]int]bool)
+	var cells []shipCell
+	ship := 0
+	for _, spec := range r.Fleet {
+		for range spec.Count {
+			for {
+				horizontal := rand.Intn(2) == 0
+				width, height := spec.Size, 1
+				if !horizontal {
+					width, height = 1, spec.Size
+				}
+				x := rand.Intn(r.BoardSize - width + 1)
+				y := rand.Intn(r.BoardSize - height + 1)
+
+				var candidate []shipCell
+				for i := range spec.Size {
+					c := shipCell{Ship: ship, Type: spec.Type, X: x, Y: y}
+					if horizontal {
+						c.X += i
+					} else {
+						c.Y += i
+					}
+					if taken[[2]int{c.X, c.Y}] {
+						candidate = nil
+						break
+					}
+					candidate = append(candidate, c)
+				}
+				if candidate == nil {
+					continue
+				}
+				for _, c := range candidate {
+					taken[[2]int{c.X, c.Y}] = true
+				}
+				cells = append(cells, candidate...)
+				break
+			}
+			ship++
+		}
+	}
+	return cells
+}
+
+func (r Rules) toProto() *gamepb.Rules {
+	fleet := make([]*gamepb.ShipSpec, 0, len(r.Fleet))
+	for _, spec := range r.Fleet {
+		fleet = append(fleet, &gamepb.ShipSpec{Type: spec.Type, Size: int32(spec.Size), Count: int32(spec.Count)})
+	}
+	return &gamepb.Rules{
+		Name:      r.Name,
+		BoardSize: int32(r.BoardSize),
+		Ships:     int32(r.Ships()),
+		Fleet:     fleet,
+	}
+}
@@ -2,183 +2,784 @@ package game
 
 import (
 	"context"
+	"errors"
 	"io"
-	"log"
-	"math/rand"
+	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
-	"slices"
-
+	"github.com/gosukretess/battleships/internal/achievement"
+	"github.com/gosukretess/battleships/internal/auth"
+	"github.com/gosukretess/battleships/internal/logging"
+	"github.com/gosukretess/battleships/internal/metrics"
+	"github.com/gosukretess/battleships/internal/notify"
+	"github.com/gosukretess/battleships/internal/ratelimit"
+	"github.com/gosukretess/battleships/internal/tracing"
+	"github.com/gosukretess/battleships/internal/user"
 	"github.com/gosukretess/battleships/proto/gamepb"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// turnCheckInterval is how often RunTurnTimeouts looks for correspondence
+// games whose turn has timed out.
+const turnCheckInterval = time.Minute
+
+// notifyTimeout limits how long delivering one notification may take.
+const notifyTimeout = 30 * time.Second
+
+// Players looks up the players to notify. The user store implements it.
+type Players interface {
+	GetUser(ctx context.Context, id string) (user.UserDto, error)
+}
+
 type Server struct {
 	gamepb.UnimplementedGameServiceServer
-	store   *Store
-	mu      sync.Mutex
-	streams []gamepb.GameService_PlayerMoveServer
+	store         *Store
+	hub           *Hub
+	players       Players
+	notifier      notify.Notifier
+	defaultRules  string
+	turnTimeout   time.Duration
+	mu            sync.Mutex
+	closing       bool
+	shutdown      chan struct{}
+	handlers      sync.WaitGroup
+	notifications sync.WaitGroup
 }
 
-func NewServer(store *Store) *Server {
+func NewServer(store *Store, hub *Hub, players Players, notifier notify.Notifier, cfg Config) *Server {
+	defaultRules := cfg.DefaultRules
+	if defaultRules == "" {
+		defaultRules = DefaultRules
+	}
+	turnTimeout := cfg.TurnTimeout
+	if turnTimeout <= 0 {
+		turnTimeout = DefaultTurnTimeout
+	}
+
 	return &Server{
-		store:   store,
-		streams: make([]gamepb.GameService_PlayerMoveServer, 0),
+		store:        store,
+		hub:          hub,
+		players:      players,
+		notifier:     notifier,
+		defaultRules: defaultRules,
+		turnTimeout:  turnTimeout,
+		shutdown:     make(chan struct{}),
 	}
 }
 
-// Just 12 ships with size 1 - this is just a game draft
-func (s *Server) CreateGame(_ context.Context, req *gamepb.CreateGameRequest) (*gamepb.CreateGameResponse, error) {
-	type Coords struct {
-		x int
-		y int
+func (s *Server) CreateGame(ctx context.Context, req *gamepb.CreateGameRequest) (*gamepb.CreateGameResponse, error) {
+	if caller, _ := auth.UserID(ctx); caller != req.GetUserId1() && caller != req.GetUserId2() {
+		return nil, status.Error(codes.PermissionDenied, "you can only create games you play in")
 	}
 
-	contains := func(coords []Coords, c Coords) bool {
-		for _, existing := range coords {
-			if existing.x == c.x && existing.y == c.y {
-				return true
-			}
+	rulesName := req.GetRules()
+	if rulesName == "" {
+		rulesName = s.defaultRules
+	}
+	rules, ok := LookupRules(rulesName)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown rule set %q", rulesName)
+	}
+	mode := ModeRealtime
+	if req.GetMode() == gamepb.GameMode_CORRESPONDENCE {
+		mode = ModeCorrespondence
+	}
+
+	gameDto, err := s.store.CreateGame(ctx, req.GetUserId1(), req.GetUserId2(), rules.Name, mode, rules.placeFleet(), rules.placeFleet())
+	if err != nil {
+		return nil, err
+	}
+	metrics.GamesCreated.Inc()
+
+	return &gamepb.CreateGameResponse{
+		Game: s.gameToProto(gameDto),
+	}, nil
+}
+
+func (s *Server) GetAllGames(ctx context.Context, req *gamepb.GetAllGamesRequest) (*gamepb.GetAllGamesResponse, error) {
+	games, err := s.store.GetGames(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var pbGames []*gamepb.Game
+	for _, g := range games {
+		pbGames = append(pbGames, s.gameToProto(g))
+	}
+
+	return &gamepb.GetAllGamesResponse{
+		Games: pbGames,
+	}, nil
+}
+
+// ListGames pages through the games matching the filters of the request.
+func (s *Server) ListGames(ctx context.Context, req *gamepb.ListGamesRequest) (*gamepb.ListGamesResponse, error) {
+	filter := GameFilter{UserId: req.GetUserId(), Rules: req.GetRules()}
+	if req.GetStatus() != gamepb.GameStatus_GAME_STATUS_UNSPECIFIED {
+		filter.Status = req.GetStatus().String()
+	}
+	if filter.Rules != "" {
+		if _, ok := LookupRules(filter.Rules); !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown rule set %q", filter.Rules)
 		}
-		return false
 	}
+	if req.GetCreatedAfter() != nil {
+		filter.CreatedAfter = req.GetCreatedAfter().AsTime()
+	}
+	if req.GetCreatedBefore() != nil {
+		filter.CreatedBefore = req.GetCreatedBefore().AsTime()
+	}
+	order := strings.Join(strings.Fields(strings.ToLower(req.GetOrderBy())), " ")
+	if order == "" {
+		order = DefaultGameOrder
+	}
+	offset, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	games, next, err := s.store.ListGames(ctx, filter, order, offset, pageSize(req.GetPageSize()))
+	if errors.Is(err, ErrInvalidOrder) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pbGames := make([]*gamepb.Game, 0, len(games))
+	for _, g := range games {
+		pbGames = append(pbGames, s.gameToProto(g))
+	}
+	return &gamepb.ListGamesResponse{
+		Games:         pbGames,
+		NextPageToken: encodePageToken(next),
+	}, nil
+}
 
-	gameDto, err := s.store.CreateGame(req.GetUserId1(), req.GetUserId2())
+func (s *Server) GetGame(ctx context.Context, req *gamepb.GetGameRequest) (*gamepb.GetGameResponse, error) {
+	game, err := s.store.GetGame(ctx, req.GetGameId())
+	if errors.Is(err, ErrGameNotFound) {
+		return nil, status.Error(codes.NotFound, "game not found")
+	}
 	if err != nil {
 		return nil, err
 	}
+	return &gamepb.GetGameResponse{Game: s.gameToProto(game)}, nil
+}
 
-	usersShips := make(map[string][]Coords)
-	usersShips[req.GetUserId1()] = make([]Coords, 0, 12)
-	usersShips[req.GetUserId2()] = make([]Coords, 0, 12)
-	for _, userId := range []string{req.GetUserId1(), req.GetUserId2()} {
-		// Generate unique 12 coords
-		for len(usersShips[userId]) < 12 {
-			x := rand.Intn(8)
-			y := rand.Intn(8)
-			ship := Coords{
-				x: x,
-				y: y,
+// Connect registers a connection of userId with the hub. It fails with
+// Unavailable once the server is shutting down. Every connection must be
+// released with Disconnect.
+func (s *Server) Connect(userId string, send func(*gamepb.GameEvent) error) (*Conn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closing {
+		return nil, status.Error(codes.Unavailable, "server is shutting down")
+	}
+	s.handlers.Add(1)
+	metrics.ActiveStreams.Inc()
+	return s.hub.Connect(userId, send), nil
+}
+
+func (s *Server) Disconnect(conn *Conn) {
+	conn.Close()
+	metrics.ActiveStreams.Dec()
+	s.handlers.Done()
+}
+
+// ShuttingDown is closed when the server starts shutting down. Connection
+// handlers should return once it is.
+func (s *Server) ShuttingDown() <-chan struct{} {
+	return s.shutdown
+}
+
+func (s *Server) PlayerMove(stream gamepb.GameService_PlayerMoveServer) error {
+	ctx := stream.Context()
+	userId, _ := auth.UserID(ctx)
+	conn, err := s.Connect(userId, stream.Send)
+	if err != nil {
+		return err
+	}
+	defer s.Disconnect(conn)
+
+	logger := logging.FromContext(ctx)
+
+	// Receive in the background so that the handler can leave on shutdown
+	// while the client is idle.
+	events := make(chan *gamepb.GameEvent)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
 			}
+		}
+	}()
 
-			if !contains(usersShips[userId], ship) {
-				usersShips[userId] = append(usersShips[userId], ship)
+	for {
+		select {
+		case <-s.shutdown:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case err := <-recvErr:
+			if err == io.EOF {
+				return nil
 			}
+			logger.Warn("cannot receive game event", "error", err)
+			return nil
+		case <-conn.Done():
+			err := conn.Err()
+			logger.Warn("game stream dropped", "error", err)
+			if errors.Is(err, ErrSlowConn) {
+				return status.Error(codes.ResourceExhausted, "too many events pending, stream dropped")
+			}
+			return nil
+		case event := <-events:
+			s.HandleEvent(ctx, conn, event)
 		}
+	}
+}
 
-		for _, coords := range usersShips[userId] {
-			s.store.AddShip(gameDto.Id, userId, coords.x, coords.y)
+// Subscribe lets conn receive the events of a game userId plays in.
+func (s *Server) Subscribe(ctx context.Context, conn *Conn, gameId, userId string) error {
+	game, err := s.store.GetGame(ctx, gameId)
+	if errors.Is(err, ErrGameNotFound) {
+		return newGameError(gamepb.ErrorCode_GAME_NOT_FOUND, "game not found")
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := game.Opponent(userId); !ok {
+		return newGameError(gamepb.ErrorCode_NOT_A_PLAYER, "user does not play in this game")
+	}
+	conn.Subscribe(gameId)
+	return nil
+}
+
+// HandleEvent processes one event a player sent on conn, in its own span.
+// Events are always sent on behalf of the authenticated caller. Events that
+// fail, or come faster than the stream event limit in ctx allows, are
+// answered with an ERROR event on conn only.
+func (s *Server) HandleEvent(ctx context.Context, conn *Conn, event *gamepb.GameEvent) {
+	if err := ratelimit.AllowEvent(ctx); err != nil {
+		logger := logging.FromContext(ctx).With("game_id", event.GameId, "user_id", event.UserId1)
+		s.sendError(logger, conn, event, newGameError(gamepb.ErrorCode_RATE_LIMITED, "too many events, slow down"))
+		return
+	}
+	caller, _ := auth.UserID(ctx)
+	if event.UserId1 == "" {
+		event.UserId1 = caller
+	}
+	if event.UserId1 != caller {
+		logger := logging.FromContext(ctx).With("game_id", event.GameId, "user_id", event.UserId1)
+		s.sendError(logger, conn, event, newGameError(gamepb.ErrorCode_NOT_A_PLAYER, "events can only be sent as the logged in user"))
+		return
+	}
+
+	ctx, span := tracing.Start(ctx, "PlayerMove/"+event.Type.String(), trace.WithAttributes(
+		attribute.String("game.id", event.GameId),
+		attribute.String("user.id", event.UserId1),
+	))
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With("game_id", event.GameId, "user_id", event.UserId1)
+	logger.Debug("received game event", "type", event.Type.String(), "x", event.X, "y", event.Y)
+
+	switch event.Type {
+	case gamepb.EventType_SUBSCRIBE:
+		if err := s.Subscribe(ctx, conn, event.GameId, event.UserId1); err != nil {
+			s.sendError(logger, conn, event, err)
 		}
+	case gamepb.EventType_UNSUBSCRIBE:
+		conn.Unsubscribe(event.GameId)
+	case gamepb.EventType_MOVE:
+		s.handleMove(ctx, logger, conn, event)
 	}
+}
 
-	parsedTime, err := time.Parse(time.RFC3339Nano, gameDto.Created)
-	createdTimestamp := timestamppb.New(parsedTime)
+func (s *Server) handleMove(ctx context.Context, logger *slog.Logger, conn *Conn, event *gamepb.GameEvent) {
+	game, err := s.prepareShot(ctx, event.GameId, event.UserId1, event.X, event.Y)
+	if err != nil {
+		s.sendError(logger, conn, event, err)
+		return
+	}
+	// Older clients only start sending once it is their turn.
+	if !conn.Subscribed(game.Id) {
+		conn.Subscribe(game.Id)
+	}
 
-	return &gamepb.CreateGameResponse{
-		Game: &gamepb.Game{
-			Id:       gameDto.Id,
-			UserId1:  gameDto.UserId1,
-			UserId2:  gameDto.UserId2,
-			Created:  createdTimestamp,
-			NextUser: gameDto.NextUser,
-		},
-	}, err
+	responseEvent, _, err := s.fire(ctx, logger, game, event.UserId1, event.X, event.Y)
+	if err != nil {
+		s.sendError(logger, conn, event, err)
+		return
+	}
+	// A repeated shot does not pass the turn, only the shooter needs to know.
+	if responseEvent.Type == gamepb.EventType_TAKEN {
+		conn.Send(responseEvent)
+	}
 }
 
-func (s *Server) GetAllGames(_ context.Context, req *gamepb.GetAllGamesRequest) (*gamepb.GetAllGamesResponse, error) {
-	games, err := s.store.GetGames()
+// sendError answers a failed event with an ERROR event on conn. Errors other
+// than gameErrors are reported as INTERNAL, without their details.
+func (s *Server) sendError(logger *slog.Logger, conn *Conn, event *gamepb.GameEvent, err error) {
+	var gameErr *gameError
+	if !errors.As(err, &gameErr) {
+		gameErr = errInternal
+	}
+	logger.Warn("game event rejected", "type", event.Type.String(), "error_code", gameErr.code.String(), "error", err)
+	metrics.EventErrors.WithLabelValues(gameErr.code.String()).Inc()
+
+	conn.Send(&gamepb.GameEvent{
+		GameId:       event.GameId,
+		UserId1:      event.UserId1,
+		X:            event.X,
+		Y:            event.Y,
+		Type:         gamepb.EventType_ERROR,
+		ErrorCode:    gameErr.code,
+		ErrorMessage: gameErr.message,
+	})
+}
 
+// prepareShot returns the game of a shot userId wants to fire at x, y, or a
+// gameError if they cannot fire it.
+func (s *Server) prepareShot(ctx context.Context, gameId, userId string, x, y int32) (GameDto, error) {
+	game, err := s.store.GetGame(ctx, gameId)
+	if errors.Is(err, ErrGameNotFound) {
+		return game, newGameError(gamepb.ErrorCode_GAME_NOT_FOUND, "game not found")
+	}
+	if err != nil {
+		return game, err
+	}
+	if _, ok := game.Opponent(userId); !ok {
+		return game, newGameError(gamepb.ErrorCode_NOT_A_PLAYER, "you do not play in this game")
+	}
+	if game.Status != StatusInProgress {
+		return game, newGameError(gamepb.ErrorCode_GAME_FINISHED, "game is already over")
+	}
+	if game.NextUser != userId {
+		return game, newGameError(gamepb.ErrorCode_NOT_YOUR_TURN, "it is not your turn")
+	}
+	if rules, ok := LookupRules(game.Rules); ok {
+		size := int32(rules.BoardSize)
+		if x < 0 || x >= size || y < 0 || y >= size {
+			return game, newGameError(gamepb.ErrorCode_OUT_OF_BOUNDS, "coordinates must be between 0 and %d", size-1)
+		}
+	}
+	return game, nil
+}
+
+// FireShot fires a shot of the caller without a stream and tells them what
+// it hit. Rejected shots fail with a gameError status.
+func (s *Server) FireShot(ctx context.Context, req *gamepb.FireShotRequest) (*gamepb.FireShotResponse, error) {
+	caller, _ := auth.UserID(ctx)
+	game, err := s.prepareShot(ctx, req.GetGameId(), caller, req.GetX(), req.GetY())
 	if err != nil {
 		return nil, err
 	}
 
-	var pbGames []*gamepb.Game
-	for _, g := range games {
-		parsedTime, _ := time.Parse(time.RFC3339Nano, g.Created)
-		createdTimestamp := timestamppb.New(parsedTime)
-		pbGames = append(pbGames, &gamepb.Game{
-			Id:       g.Id,
-			UserId1:  g.UserId1,
-			UserId2:  g.UserId2,
-			Created:  createdTimestamp,
-			NextUser: g.NextUser,
-		})
+	logger := logging.FromContext(ctx).With("game_id", game.Id, "user_id", caller)
+	event, result, err := s.fire(ctx, logger, game, caller, req.GetX(), req.GetY())
+	if err != nil {
+		return nil, err
 	}
 
-	return &gamepb.GetAllGamesResponse{
-		Games: pbGames,
+	resp := &gamepb.FireShotResponse{Event: event, Result: gamepb.FireShotResponse_MISS}
+	switch {
+	case event.Type == gamepb.EventType_TAKEN:
+		resp.Result = gamepb.FireShotResponse_ALREADY_TAKEN
+	case result.Won:
+		resp.Result, resp.ShipType, resp.Winner = gamepb.FireShotResponse_GAME_OVER, result.Sunk, caller
+	case result.Sunk != "":
+		resp.Result, resp.ShipType = gamepb.FireShotResponse_SUNK, result.Sunk
+	case result.Hit:
+		resp.Result = gamepb.FireShotResponse_HIT
+	}
+	return resp, nil
+}
+
+// fire records a shot of userId in game and publishes the result to the
+// players. A repeated shot is only returned, as TAKEN. Shots the game no
+// longer accepts fail with a gameError, failures of the store are logged
+// before they are returned.
+func (s *Server) fire(ctx context.Context, logger *slog.Logger, game GameDto, userId string, x, y int32) (*gamepb.GameEvent, MoveResult, error) {
+	opponent, _ := game.Opponent(userId)
+
+	eventType := gamepb.EventType_MISS
+	result, err := s.store.Move(ctx, game.Id, userId, int(x), int(y))
+	if errors.Is(err, ErrCoordsTaken) {
+		eventType = gamepb.EventType_TAKEN
+	} else if errors.Is(err, ErrGameOver) {
+		return nil, result, newGameError(gamepb.ErrorCode_GAME_FINISHED, "game is already over")
+	} else if errors.Is(err, ErrNotYourTurn) {
+		return nil, result, newGameError(gamepb.ErrorCode_NOT_YOUR_TURN, "it is not your turn")
+	} else if errors.Is(err, ErrGameNotFound) {
+		// The game was deleted after prepareShot read it.
+		return nil, result, newGameError(gamepb.ErrorCode_GAME_NOT_FOUND, "game not found")
+	} else if err != nil {
+		logger.Error("move failed", "x", x, "y", y, "error", err)
+		trace.SpanFromContext(ctx).SetStatus(otelcodes.Error, err.Error())
+		return nil, result, err
+	} else if result.Hit {
+		eventType = gamepb.EventType_HIT
+	}
+
+	metrics.Moves.WithLabelValues(eventType.String()).Inc()
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("move.result", eventType.String()))
+
+	responseEvent := &gamepb.GameEvent{
+		GameId:  game.Id,
+		UserId1: userId,
+		UserId2: opponent,
+		X:       x,
+		Y:       y,
+		Type:    eventType,
+	}
+
+	logger.Info("move processed", "x", x, "y", y, "result", eventType.String(), "sunk", result.Sunk)
+	if eventType == gamepb.EventType_TAKEN {
+		return responseEvent, result, nil
+	}
+	s.hub.Publish(game.Id, responseEvent)
+
+	// Achievements come before GAME_OVER, after which clients stop listening.
+	s.checkAchievements(ctx, logger, game, userId, result, result.Won)
+	if result.Won {
+		logger.Info("game won")
+		s.gameOver(ctx, game, StatusFinished, userId, opponent)
+	} else if game.Mode == ModeCorrespondence && !s.hub.Watching(game.Id, opponent) {
+		s.notify(ctx, notify.Notification{
+			Kind:     notify.YourTurn,
+			GameId:   game.Id,
+			Deadline: time.Now().Add(s.turnTimeout),
+		}, opponent, userId)
+	}
+	return responseEvent, result, nil
+}
+
+// GetInbox pages through the games waiting for the caller to move.
+func (s *Server) GetInbox(ctx context.Context, req *gamepb.GetInboxRequest) (*gamepb.GetInboxResponse, error) {
+	caller, _ := auth.UserID(ctx)
+	offset, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := GameFilter{NextUser: caller, Status: StatusInProgress}
+	games, next, err := s.store.ListGames(ctx, filter, "turn", offset, pageSize(req.GetPageSize()))
+	if err != nil {
+		return nil, err
+	}
+
+	pbGames := make([]*gamepb.Game, 0, len(games))
+	for _, g := range games {
+		pbGames = append(pbGames, s.gameToProto(g))
+	}
+	return &gamepb.GetInboxResponse{
+		Games:         pbGames,
+		NextPageToken: encodePageToken(next),
 	}, nil
 }
 
-func (s *Server) PlayerMove(stream gamepb.GameService_PlayerMoveServer) error {
-	s.mu.Lock()
-	if !containsStream(s.streams, stream) {
-		s.streams = append(s.streams, stream)
+// ResignGame ends a game in progress as lost for the caller.
+func (s *Server) ResignGame(ctx context.Context, req *gamepb.ResignGameRequest) (*gamepb.ResignGameResponse, error) {
+	game, err := s.store.GetGame(ctx, req.GetGameId())
+	if errors.Is(err, ErrGameNotFound) {
+		return nil, status.Error(codes.NotFound, "game not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	caller, _ := auth.UserID(ctx)
+	winner, ok := game.Opponent(caller)
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "you do not play in this game")
 	}
-	s.mu.Unlock()
 
-	var gameId string
+	finished, err := s.store.Resign(ctx, game.Id, winner)
+	if errors.Is(err, ErrGameOver) {
+		return nil, status.Error(codes.FailedPrecondition, "game is already over")
+	}
+	if err != nil {
+		return nil, err
+	}
+	game.Status, game.Winner, game.Finished = StatusResigned, winner, finished
 
-	for {
-		event, err := stream.Recv()
-		if err == io.EOF {
-			log.Println("Stream ended")
-			break
-		}
-		if err != nil {
-			log.Printf("Error: %v", err)
-			break
+	logging.FromContext(ctx).Info("game resigned", "user_id", caller)
+	s.checkAchievements(ctx, logging.FromContext(ctx).With("user_id", winner), game, winner, MoveResult{}, true)
+	s.gameOver(ctx, game, StatusResigned, winner, caller)
+	return &gamepb.ResignGameResponse{Game: s.gameToProto(game)}, nil
+}
+
+// GetLeaderboard pages through the ratings, best first.
+func (s *Server) GetLeaderboard(ctx context.Context, req *gamepb.GetLeaderboardRequest) (*gamepb.GetLeaderboardResponse, error) {
+	if req.GetRules() != "" {
+		if _, ok := LookupRules(req.GetRules()); !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown rule set %q", req.GetRules())
 		}
+	}
+	offset, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
-		gameId = event.GameId
-		log.Printf("[Game] Received event: %+v", event)
+	ratings, next, err := s.store.GetLeaderboard(ctx, req.GetRules(), offset, pageSize(req.GetPageSize()))
+	if err != nil {
+		return nil, err
+	}
 
-		if event.Type == gamepb.EventType_MOVE {
-			eventType := gamepb.EventType_MISS
+	entries := make([]*gamepb.LeaderboardEntry, 0, len(ratings))
+	for i, r := range ratings {
+		entries = append(entries, &gamepb.LeaderboardEntry{
+			Rank:   int32(offset + i + 1),
+			UserId: r.UserId,
+			Name:   r.Name,
+			Rules:  r.Rules,
+			Rating: int32(r.Rating),
+			Games:  int32(r.Games),
+		})
+	}
+	return &gamepb.GetLeaderboardResponse{
+		Entries:       entries,
+		NextPageToken: encodePageToken(next),
+	}, nil
+}
 
-			taken, _ := s.store.AreCoordsTaken(gameId, event.UserId1, int(event.X), int(event.Y))
+func (s *Server) GetRatingHistory(ctx context.Context, req *gamepb.GetRatingHistoryRequest) (*gamepb.GetRatingHistoryResponse, error) {
+	changes, err := s.store.GetRatingHistory(ctx, req.GetUserId(), req.GetRules())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*gamepb.RatingChange, 0, len(changes))
+	for _, c := range changes {
+		created, _ := time.Parse(time.RFC3339, c.Created)
+		result = append(result, &gamepb.RatingChange{
+			GameId:       c.GameId,
+			Rules:        c.Rules,
+			OpponentId:   c.OpponentId,
+			Won:          c.Won,
+			RatingBefore: int32(c.RatingBefore),
+			RatingAfter:  int32(c.RatingAfter),
+			Created:      timestamppb.New(created),
+		})
+	}
+	return &gamepb.GetRatingHistoryResponse{Changes: result}, nil
+}
+
+// gameOver tells both players who won and disconnects them from the game.
+// Players of a correspondence game are also notified.
+func (s *Server) gameOver(ctx context.Context, game GameDto, gameStatus, winner, loser string) {
+	s.publishGameOver(game, gameStatus, winner, loser)
+	if game.Mode == ModeCorrespondence {
+		s.notify(ctx, notify.Notification{Kind: notify.GameOver, GameId: game.Id, Won: true}, winner, loser)
+		s.notify(ctx, notify.Notification{Kind: notify.GameOver, GameId: game.Id}, loser, winner)
+	}
+}
 
-			log.Printf("%v", taken)
-			if taken {
-				eventType = gamepb.EventType_TAKEN
-			} else {
-				hit, err := s.store.Move(gameId, event.UserId1, int(event.X), int(event.Y))
-				if err != nil {
-					continue
-				}
+// publishGameOver sends GAME_OVER to the streams of a game that has ended and
+// disconnects them from it.
+func (s *Server) publishGameOver(game GameDto, gameStatus, winner, loser string) {
+	metrics.GamesFinished.WithLabelValues(gameStatus).Inc()
+	s.hub.Publish(game.Id, &gamepb.GameEvent{
+		GameId:  game.Id,
+		UserId1: winner,
+		UserId2: loser,
+		Type:    gamepb.EventType_GAME_OVER,
+	})
+	s.hub.Finish(game.Id)
+}
 
-				if hit {
-					eventType = gamepb.EventType_HIT
-				}
+// ResignGames ends every game userId is playing in favour of the opponent,
+// without changing ratings, and tells the opponents. It is called before
+// userId's account is deleted, so only the opponents of correspondence games
+// are notified. It returns the number of games ended.
+func (s *Server) ResignGames(ctx context.Context, userId string) (int, error) {
+	filter := GameFilter{UserId: userId, Status: StatusInProgress}
+	resigned := 0
+	for {
+		// Ended games drop out of the filter, so every page is the first.
+		games, next, err := s.store.ListGames(ctx, filter, "created", 0, maxPageSize)
+		if err != nil {
+			return resigned, err
+		}
+		for _, game := range games {
+			winner, _ := game.Opponent(userId)
+			finished, err := s.store.Abandon(ctx, game.Id, winner)
+			if errors.Is(err, ErrGameOver) {
+				continue
+			}
+			if err != nil {
+				return resigned, err
 			}
+			game.Status, game.Winner, game.Finished = StatusResigned, winner, finished
+			resigned++
 
-			responseEvent := gamepb.GameEvent{
-				GameId:  gameId,
-				UserId1: event.UserId1,
-				UserId2: event.UserId2,
-				X:       event.X,
-				Y:       event.Y,
-				Type:    eventType,
+			logging.FromContext(ctx).Info("game resigned by deleting the account", "game_id", game.Id, "user_id", userId)
+			s.publishGameOver(game, StatusResigned, winner, userId)
+			if game.Mode == ModeCorrespondence {
+				s.notify(ctx, notify.Notification{Kind: notify.GameOver, GameId: game.Id, Won: true}, winner, userId)
 			}
+		}
+		if next == 0 {
+			return resigned, nil
+		}
+	}
+}
+
+// notify sends n to userId in the background, so that a slow sink does not
+// hold up the move. Failures are only logged.
+func (s *Server) notify(ctx context.Context, n notify.Notification, userId, opponent string) {
+	ctx = context.WithoutCancel(ctx)
+	logger := logging.FromContext(ctx).With("game_id", n.GameId, "user_id", userId, "notification", n.Kind)
+
+	s.notifications.Add(1)
+	go func() {
+		defer s.notifications.Done()
+		ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+		defer cancel()
+
+		player, err := s.players.GetUser(ctx, userId)
+		if err == nil {
+			var other user.UserDto
+			other, err = s.players.GetUser(ctx, opponent)
+			n.UserId, n.Name, n.Email, n.Opponent = player.Id, player.Name, player.Email, other.Name
+		}
+		if err == nil {
+			err = s.notifier.Notify(ctx, n)
+		}
+		if err != nil {
+			logger.Warn("cannot notify player", "error", err)
+			metrics.Notifications.WithLabelValues(string(n.Kind), "failed").Inc()
+			return
+		}
+		logger.Debug("player notified")
+		metrics.Notifications.WithLabelValues(string(n.Kind), "sent").Inc()
+	}()
+}
 
-			log.Printf("[Game] Sending event: %+v", responseEvent)
+// RunTurnTimeouts ends correspondence games whose turn has timed out, as lost
+// for the player who did not move. It checks right away and then periodically
+// until ctx is done or the server shuts down.
+func (s *Server) RunTurnTimeouts(ctx context.Context) {
+	ticker := time.NewTicker(turnCheckInterval)
+	defer ticker.Stop()
 
-			for _, userStream := range s.streams {
-				userStream.Send(&responseEvent)
-			}
+	for {
+		s.expireTurns(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.shutdown:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) expireTurns(ctx context.Context) {
+	games, err := s.store.ExpiredTurns(ctx, time.Now().Add(-s.turnTimeout))
+	if err != nil {
+		logging.FromContext(ctx).Error("cannot look for timed out turns", "error", err)
+		return
+	}
+	for _, game := range games {
+		loser := game.NextUser
+		winner, _ := game.Opponent(loser)
+		logger := logging.FromContext(ctx).With("game_id", game.Id)
+
+		finished, err := s.store.Forfeit(ctx, game)
+		if errors.Is(err, ErrGameOver) {
+			// The player moved or the game ended in the meantime.
+			continue
 		}
+		if err != nil {
+			logger.Error("cannot end game after turn timeout", "error", err)
+			continue
+		}
+		game.Status, game.Winner, game.Finished = StatusResigned, winner, finished
+
+		logger.Info("turn timed out", "user_id", loser)
+		s.checkAchievements(ctx, logger.With("user_id", winner), game, winner, MoveResult{}, true)
+		s.gameOver(ctx, game, StatusResigned, winner, loser)
 	}
+}
 
+// checkAchievements unlocks the achievements userId earned with a shot or a
+// win and tells them on their streams. The logger should name the game and
+// the user. Errors are only logged; they do not affect the game.
+func (s *Server) checkAchievements(ctx context.Context, logger *slog.Logger, game GameDto, userId string, result MoveResult, won bool) {
+	if !won && result.Sunk == "" {
+		return
+	}
+	unlocked, err := s.store.CheckAchievements(ctx, game, userId, result, won)
+	if err != nil {
+		logger.Error("cannot check achievements", "error", err)
+	}
+	for _, a := range unlocked {
+		info, _ := achievement.Lookup(a.Id)
+		logger.Info("achievement unlocked", "achievement", a.Id)
+		metrics.AchievementsUnlocked.WithLabelValues(a.Id).Inc()
+		s.hub.PublishTo(game.Id, userId, &gamepb.GameEvent{
+			GameId:      game.Id,
+			UserId1:     userId,
+			Type:        gamepb.EventType_ACHIEVEMENT,
+			Achievement: info.ToProto(a.GameId, a.Unlocked),
+		})
+	}
+}
+
+// Shutdown stops accepting new game streams, tells connected players that the
+// server is going away and waits until moves already being processed have been
+// committed, every stream has been closed and pending notifications have been
+// delivered, or until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
 	s.mu.Lock()
-	s.streams = removeStream(s.streams, stream)
+	if s.closing {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closing = true
 	s.mu.Unlock()
 
-	return nil
+	s.hub.Broadcast(&gamepb.GameEvent{Type: gamepb.EventType_SERVER_SHUTDOWN})
+	close(s.shutdown)
+
+	done := make(chan struct{})
+	go func() {
+		s.handlers.Wait()
+		s.notifications.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
+// GetShips only shows players their own fleet.
 func (s *Server) GetShips(ctx context.Context, req *gamepb.GetShipsRequest) (*gamepb.GetShipsResponse, error) {
-	ships, err := s.store.GetShips(req.GetGameId(), req.GetUserId())
+	if caller, _ := auth.UserID(ctx); caller != req.GetUserId() {
+		return nil, status.Error(codes.PermissionDenied, "you can only see your own ships")
+	}
+
+	ships, err := s.store.GetShips(ctx, req.GetGameId(), req.GetUserId())
 	if err != nil {
 		return nil, err
 	}
@@ -190,14 +791,28 @@ func (s *Server) GetShips(ctx context.Context, req *gamepb.GetShipsRequest) (*ga
 			UserId: ship.UserId,
 			X:      int32(ship.X),
 			Y:      int32(ship.Y),
+			Type:   ship.Type,
 		})
 	}
 
 	return &gamepb.GetShipsResponse{Ships: result}, nil
 }
 
+// GetMoves only shows the moves of a game to its players, who see the shots
+// of both sides on their boards anyway.
 func (s *Server) GetMoves(ctx context.Context, req *gamepb.GetMovesRequest) (*gamepb.GetMovesResponse, error) {
-	moves, err := s.store.GetMoves(req.GetGameId(), req.GetUserId())
+	game, err := s.store.GetGame(ctx, req.GetGameId())
+	if errors.Is(err, ErrGameNotFound) {
+		return nil, status.Error(codes.NotFound, "game not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if caller, _ := auth.UserID(ctx); caller != game.UserId1 && caller != game.UserId2 {
+		return nil, status.Error(codes.PermissionDenied, "you can only see the moves of your own games")
+	}
+
+	moves, err := s.store.GetMoves(ctx, req.GetGameId(), req.GetUserId())
 	if err != nil {
 		return nil, err
 	}
@@ -218,16 +833,43 @@ func (s *Server) GetMoves(ctx context.Context, req *gamepb.GetMovesRequest) (*ga
 	}, nil
 }
 
-func containsStream(streams []gamepb.GameService_PlayerMoveServer, s gamepb.GameService_PlayerMoveServer) bool {
-	return slices.Contains(streams, s)
+// turnDeadline returns when the current turn of a correspondence game in
+// progress times out.
+func (s *Server) turnDeadline(g GameDto) (time.Time, bool) {
+	if g.Mode != ModeCorrespondence || g.Status != StatusInProgress {
+		return time.Time{}, false
+	}
+	started, err := time.Parse(time.RFC3339, g.TurnStarted)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return started.Add(s.turnTimeout), true
 }
 
-func removeStream(streams []gamepb.GameService_PlayerMoveServer, s gamepb.GameService_PlayerMoveServer) []gamepb.GameService_PlayerMoveServer {
-	newStreams := []gamepb.GameService_PlayerMoveServer{}
-	for _, str := range streams {
-		if str != s {
-			newStreams = append(newStreams, str)
-		}
+func (s *Server) gameToProto(g GameDto) *gamepb.Game {
+	parsedTime, _ := time.Parse(time.RFC3339Nano, g.Created)
+
+	var pbRules *gamepb.Rules
+	if rules, ok := LookupRules(g.Rules); ok {
+		pbRules = rules.toProto()
+	}
+
+	game := &gamepb.Game{
+		Id:       g.Id,
+		UserId1:  g.UserId1,
+		UserId2:  g.UserId2,
+		Created:  timestamppb.New(parsedTime),
+		NextUser: g.NextUser,
+		Rules:    pbRules,
+		Status:   gamepb.GameStatus(gamepb.GameStatus_value[g.Status]),
+		Winner:   g.Winner,
+		Mode:     gamepb.GameMode(gamepb.GameMode_value[g.Mode]),
+	}
+	if finished, err := time.Parse(time.RFC3339, g.Finished); err == nil {
+		game.Finished = timestamppb.New(finished)
+	}
+	if deadline, ok := s.turnDeadline(g); ok {
+		game.TurnDeadline = timestamppb.New(deadline)
 	}
-	return newStreams
+	return game
 }
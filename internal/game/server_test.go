@@ -0,0 +1,712 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gosukretess/battleships/internal/auth"
+	"github.com/gosukretess/battleships/internal/database/dbtest"
+	"github.com/gosukretess/battleships/internal/notify"
+	"github.com/gosukretess/battleships/internal/presence"
+	"github.com/gosukretess/battleships/internal/ratelimit"
+	"github.com/gosukretess/battleships/internal/user"
+	"github.com/gosukretess/battleships/proto/gamepb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const carol = "carol"
+
+func newTestServer(t *testing.T, backend string) *Server {
+	t.Helper()
+
+	store := newTestStore(t, backend)
+	if _, err := store.db.Exec("INSERT INTO users(id, name, email) VALUES (?, ?, ?)", carol, carol, carol+"@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	return NewServer(store, NewHub(presence.NewTracker()), user.NewStore(store.db), &recordingNotifier{}, Config{})
+}
+
+// as returns a context authenticated as userId.
+func as(userId string) context.Context {
+	return auth.WithUserID(context.Background(), userId)
+}
+
+func TestGetMoves(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		s := newTestServer(t, backend)
+		g := createGame(t, s.store, ModeRealtime)
+		if _, err := s.store.Move(context.Background(), g.Id, alice, 5, 5); err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			caller, gameId, userId string
+			want                   codes.Code
+			moves                  int
+		}{
+			{caller: alice, gameId: g.Id, userId: alice, moves: 1},
+			{caller: bob, gameId: g.Id, userId: alice, moves: 1},
+			{caller: alice, gameId: g.Id, userId: bob, moves: 0},
+			{caller: carol, gameId: g.Id, userId: alice, want: codes.PermissionDenied},
+			{caller: alice, gameId: "no-such-game", userId: alice, want: codes.NotFound},
+		}
+		for _, tt := range tests {
+			resp, err := s.GetMoves(as(tt.caller), &gamepb.GetMovesRequest{GameId: tt.gameId, UserId: tt.userId})
+			if status.Code(err) != tt.want {
+				t.Errorf("%s reading the moves of %s in %s: error %v, want %v", tt.caller, tt.userId, tt.gameId, err, tt.want)
+				continue
+			}
+			if len(resp.GetMoves()) != tt.moves {
+				t.Errorf("%s reading the moves of %s: %d moves, want %d", tt.caller, tt.userId, len(resp.GetMoves()), tt.moves)
+			}
+		}
+	})
+}
+
+// recordingNotifier keeps the notifications sent, in the order they were sent.
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []notify.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification notify.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func (n *recordingNotifier) Sent() []notify.Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return slices.Clone(n.sent)
+}
+
+// connect connects userId to the hub of s and returns the events it receives.
+func connect(t *testing.T, s *Server, userId string) (*Conn, <-chan *gamepb.GameEvent) {
+	t.Helper()
+
+	events := make(chan *gamepb.GameEvent, 16)
+	conn, err := s.Connect(userId, func(event *gamepb.GameEvent) error {
+		events <- event
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Disconnect(conn) })
+	return conn, events
+}
+
+// next returns the next event of a connection, failing the test if none
+// arrives in time.
+func next(t *testing.T, events <-chan *gamepb.GameEvent) *gamepb.GameEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+		return nil
+	}
+}
+
+func TestResignGames(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		s := newTestServer(t, backend)
+		realtime := createGame(t, s.store, ModeRealtime)
+		correspondence, err := s.store.CreateGame(ctx, carol, alice, DefaultRules, ModeCorrespondence, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		over := createGame(t, s.store, ModeRealtime)
+		if _, err := s.store.Resign(ctx, over.Id, alice); err != nil {
+			t.Fatal(err)
+		}
+		conn, events := connect(t, s, bob)
+		if err := s.Subscribe(ctx, conn, realtime.Id, bob); err != nil {
+			t.Fatal(err)
+		}
+
+		resigned, err := s.ResignGames(as(alice), alice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resigned != 2 {
+			t.Errorf("ResignGames ended %d games, want 2", resigned)
+		}
+
+		for id, winner := range map[string]string{realtime.Id: bob, correspondence.Id: carol} {
+			g, err := s.store.GetGame(ctx, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g.Status != StatusResigned || g.Winner != winner {
+				t.Errorf("game %s: status %s, winner %q, want %s won by %s", id, g.Status, g.Winner, StatusResigned, winner)
+			}
+		}
+		if event := next(t, events); event.Type != gamepb.EventType_GAME_OVER || event.UserId1 != bob {
+			t.Errorf("bob received %v, want GAME_OVER won by bob", event)
+		}
+		if conn.Subscribed(realtime.Id) {
+			t.Error("bob is still subscribed to the resigned game")
+		}
+
+		s.notifications.Wait()
+		sent := s.notifier.(*recordingNotifier).Sent()
+		if len(sent) != 1 || sent[0].UserId != carol || sent[0].Kind != notify.GameOver || !sent[0].Won {
+			t.Errorf("notifications = %+v, want only carol told she won", sent)
+		}
+
+		// Only the game resigned by hand counts for the ratings.
+		leaders, _, err := s.store.GetLeaderboard(ctx, DefaultRules, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range leaders {
+			if r.Games != 1 {
+				t.Errorf("%s has %d rated games, want 1", r.UserId, r.Games)
+			}
+		}
+	})
+}
+
+func TestHandleEventRateLimited(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		s := newTestServer(t, backend)
+		g := createGame(t, s.store, ModeRealtime)
+		conn, events := connect(t, s, alice)
+
+		limiter := ratelimit.New(ratelimit.Config{StreamEvents: ratelimit.Limit{Rate: 0.001, Burst: 1}})
+		ctx := limiter.WithEventLimit(as(alice), gamepb.GameService_PlayerMove_FullMethodName, alice)
+
+		s.HandleEvent(ctx, conn, &gamepb.GameEvent{Type: gamepb.EventType_SUBSCRIBE, GameId: g.Id})
+		s.HandleEvent(ctx, conn, &gamepb.GameEvent{Type: gamepb.EventType_MOVE, GameId: g.Id, X: 1, Y: 1})
+		if event := next(t, events); event.Type != gamepb.EventType_ERROR || event.ErrorCode != gamepb.ErrorCode_RATE_LIMITED {
+			t.Errorf("event over the limit answered with %v, want a RATE_LIMITED error", event)
+		}
+		// The rejected shot was not fired, and the connection stays subscribed.
+		if moves, _ := s.store.GetMoves(context.Background(), g.Id, alice); len(moves) != 0 {
+			t.Errorf("alice has %d moves after a rejected shot", len(moves))
+		}
+		if !conn.Subscribed(g.Id) {
+			t.Error("connection lost its game after a rejected event")
+		}
+	})
+}
+
+func TestListGames(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		s := newTestServer(t, backend)
+		// Created on the first, second and third of January.
+		var ids []string
+		for i, players := range [][2]string{{alice, bob}, {carol, alice}, {bob, carol}} {
+			rules := DefaultRules
+			if i == 2 {
+				rules = "classic"
+			}
+			g, err := s.store.CreateGame(ctx, players[0], players[1], rules, ModeRealtime, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			created := time.Date(2026, time.January, i+1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+			if _, err := s.store.db.Exec("UPDATE games SET created = ? WHERE id = ?", created, g.Id); err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, g.Id)
+		}
+		if _, err := s.store.Resign(ctx, ids[1], carol); err != nil {
+			t.Fatal(err)
+		}
+		day := func(d int) *timestamppb.Timestamp {
+			return timestamppb.New(time.Date(2026, time.January, d, 0, 0, 0, 0, time.UTC))
+		}
+
+		tests := []struct {
+			name string
+			req  *gamepb.ListGamesRequest
+			want []string
+		}{
+			{name: "all", req: &gamepb.ListGamesRequest{}, want: []string{ids[2], ids[1], ids[0]}},
+			{name: "oldest first", req: &gamepb.ListGamesRequest{OrderBy: " Created "}, want: ids},
+			{name: "user", req: &gamepb.ListGamesRequest{UserId: alice}, want: []string{ids[1], ids[0]}},
+			{name: "status", req: &gamepb.ListGamesRequest{Status: gamepb.GameStatus_IN_PROGRESS}, want: []string{ids[2], ids[0]}},
+			{name: "rules", req: &gamepb.ListGamesRequest{Rules: "classic"}, want: []string{ids[2]}},
+			{name: "created after", req: &gamepb.ListGamesRequest{CreatedAfter: day(2)}, want: []string{ids[2], ids[1]}},
+			{name: "created before", req: &gamepb.ListGamesRequest{CreatedBefore: day(2)}, want: []string{ids[0]}},
+			{name: "finished first", req: &gamepb.ListGamesRequest{OrderBy: "finished", PageSize: 1}, want: []string{ids[1]}},
+		}
+		for _, tt := range tests {
+			resp, err := s.ListGames(as(carol), tt.req)
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+				continue
+			}
+			var got []string
+			for _, g := range resp.Games {
+				got = append(got, g.Id)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("%s: games %v, want %v", tt.name, got, tt.want)
+			}
+		}
+
+		for _, req := range []*gamepb.ListGamesRequest{
+			{Rules: "no-such-rules"},
+			{OrderBy: "name"},
+			{PageToken: "!!"},
+		} {
+			if _, err := s.ListGames(as(carol), req); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("ListGames(%v): error %v, want InvalidArgument", req, err)
+			}
+		}
+	})
+}
+
+func TestListGamesPages(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		s := newTestServer(t, backend)
+		want := map[string]bool{}
+		for range 5 {
+			want[createGame(t, s.store, ModeRealtime).Id] = true
+		}
+
+		seen := map[string]bool{}
+		req := &gamepb.ListGamesRequest{UserId: alice, OrderBy: "created", PageSize: 2}
+		for pages := 1; ; pages++ {
+			resp, err := s.ListGames(as(alice), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Games) > 2 {
+				t.Errorf("page %d has %d games", pages, len(resp.Games))
+			}
+			for _, g := range resp.Games {
+				if seen[g.Id] {
+					t.Errorf("game %s on two pages", g.Id)
+				}
+				seen[g.Id] = true
+			}
+			if resp.NextPageToken == "" {
+				if pages != 3 {
+					t.Errorf("%d pages, want 3", pages)
+				}
+				break
+			}
+			if pages > 3 {
+				t.Fatal("pages do not end")
+			}
+			req.PageToken = resp.NextPageToken
+		}
+		if len(seen) != len(want) {
+			t.Errorf("paged through %d games, want %d", len(seen), len(want))
+		}
+	})
+}
+
+func TestHandleEventSubscriptions(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		s := newTestServer(t, backend)
+		g1 := createGame(t, s.store, ModeRealtime)
+		g2, err := s.store.CreateGame(ctx, carol, alice, DefaultRules, ModeRealtime, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g3, err := s.store.CreateGame(ctx, bob, carol, DefaultRules, ModeRealtime, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		aliceConn, aliceEvents := connect(t, s, alice)
+		bobConn, bobEvents := connect(t, s, bob)
+
+		// One stream follows every game of bob, and none of the others.
+		s.HandleEvent(as(bob), bobConn, &gamepb.GameEvent{Type: gamepb.EventType_SUBSCRIBE, GameId: g1.Id})
+		s.HandleEvent(as(bob), bobConn, &gamepb.GameEvent{Type: gamepb.EventType_SUBSCRIBE, GameId: g3.Id})
+		s.HandleEvent(as(bob), bobConn, &gamepb.GameEvent{Type: gamepb.EventType_SUBSCRIBE, GameId: g2.Id})
+		if event := next(t, bobEvents); event.Type != gamepb.EventType_ERROR || event.ErrorCode != gamepb.ErrorCode_NOT_A_PLAYER || event.GameId != g2.Id {
+			t.Errorf("subscribing to a game of others answered with %v, want a NOT_A_PLAYER error", event)
+		}
+		if !bobConn.Subscribed(g1.Id) || !bobConn.Subscribed(g3.Id) || bobConn.Subscribed(g2.Id) {
+			t.Error("bob's stream is not subscribed to exactly his games")
+		}
+
+		s.HandleEvent(as(alice), aliceConn, &gamepb.GameEvent{Type: gamepb.EventType_MOVE, GameId: g1.Id, X: 5, Y: 5})
+		if event := next(t, aliceEvents); event.Type != gamepb.EventType_MISS || event.GameId != g1.Id {
+			t.Errorf("alice received %v, want her miss in %s", event, g1.Id)
+		}
+		if event := next(t, bobEvents); event.Type != gamepb.EventType_MISS || event.GameId != g1.Id {
+			t.Errorf("bob received %v, want the miss of alice in %s", event, g1.Id)
+		}
+
+		s.HandleEvent(as(bob), bobConn, &gamepb.GameEvent{Type: gamepb.EventType_UNSUBSCRIBE, GameId: g1.Id})
+		s.hub.Publish(g1.Id, &gamepb.GameEvent{Type: gamepb.EventType_MISS, GameId: g1.Id})
+		s.HandleEvent(as(bob), bobConn, &gamepb.GameEvent{Type: gamepb.EventType_MOVE, GameId: g3.Id, X: 0, Y: 0})
+		if event := next(t, bobEvents); event.GameId != g3.Id {
+			t.Errorf("bob received %v after leaving %s, want his shot in %s", event, g1.Id, g3.Id)
+		}
+	})
+}
+
+// startedTurn moves the start of the current turn of a game to the past.
+func startedTurn(t *testing.T, s *Server, gameId string, ago time.Duration) {
+	t.Helper()
+
+	started := time.Now().Add(-ago).UTC().Format(time.RFC3339)
+	if _, err := s.store.db.Exec("UPDATE games SET turnstarted = ? WHERE id = ?", started, gameId); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpireTurns(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		s := newTestServer(t, backend)
+		expired := createGame(t, s.store, ModeCorrespondence)
+		startedTurn(t, s, expired.Id, DefaultTurnTimeout+time.Minute)
+		fresh := createGame(t, s.store, ModeCorrespondence)
+		startedTurn(t, s, fresh.Id, DefaultTurnTimeout-time.Minute)
+		realtime := createGame(t, s.store, ModeRealtime)
+		startedTurn(t, s, realtime.Id, DefaultTurnTimeout+time.Minute)
+		conn, events := connect(t, s, bob)
+		if err := s.Subscribe(ctx, conn, expired.Id, bob); err != nil {
+			t.Fatal(err)
+		}
+
+		s.expireTurns(ctx)
+
+		for _, tt := range []struct {
+			game           GameDto
+			status, winner string
+		}{
+			{game: expired, status: StatusResigned, winner: bob},
+			{game: fresh, status: StatusInProgress},
+			{game: realtime, status: StatusInProgress},
+		} {
+			g, err := s.store.GetGame(ctx, tt.game.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g.Status != tt.status || g.Winner != tt.winner {
+				t.Errorf("%s game: status %s, winner %q, want %s, %q", tt.game.Mode, g.Status, g.Winner, tt.status, tt.winner)
+			}
+		}
+		if event := next(t, events); event.Type != gamepb.EventType_ACHIEVEMENT || event.GetAchievement().GetId() != "FIRST_WIN" {
+			t.Errorf("bob received %v, want his first win", event)
+		}
+		if event := next(t, events); event.Type != gamepb.EventType_GAME_OVER || event.GameId != expired.Id || event.UserId1 != bob {
+			t.Errorf("bob received %v, want GAME_OVER won by bob", event)
+		}
+
+		s.notifications.Wait()
+		sent := s.notifier.(*recordingNotifier).Sent()
+		slices.SortFunc(sent, func(a, b notify.Notification) int { return strings.Compare(a.UserId, b.UserId) })
+		want := []notify.Notification{
+			{Kind: notify.GameOver, GameId: expired.Id, UserId: alice, Name: alice, Email: alice + "@example.com", Opponent: bob},
+			{Kind: notify.GameOver, GameId: expired.Id, UserId: bob, Name: bob, Email: bob + "@example.com", Opponent: alice, Won: true},
+		}
+		if !slices.Equal(sent, want) {
+			t.Errorf("notifications = %+v, want %+v", sent, want)
+		}
+
+		// A second check finds nothing left to do.
+		s.expireTurns(ctx)
+		s.notifications.Wait()
+		if n := len(s.notifier.(*recordingNotifier).Sent()); n != 2 {
+			t.Errorf("%d notifications after checking again, want 2", n)
+		}
+	})
+}
+
+func TestRunTurnTimeouts(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		s := newTestServer(t, backend)
+		g := createGame(t, s.store, ModeCorrespondence)
+		startedTurn(t, s, g.Id, DefaultTurnTimeout+time.Minute)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			s.RunTurnTimeouts(ctx)
+			close(done)
+		}()
+
+		// The first check runs right away.
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			current, err := s.store.GetGame(context.Background(), g.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if current.Status == StatusResigned {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("timed out turn not ended")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("RunTurnTimeouts did not return after ctx was done")
+		}
+		s.notifications.Wait()
+	})
+}
+
+func TestFireShot(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		s := newTestServer(t, backend)
+		g, err := s.store.CreateGame(context.Background(), alice, bob, DefaultRules, ModeRealtime,
+			[]shipCell{{Ship: 0, Type: Boat, X: 3, Y: 3}},
+			[]shipCell{{Ship: 0, Type: Destroyer, X: 0, Y: 0}, {Ship: 0, Type: Destroyer, X: 1, Y: 0}, {Ship: 1, Type: Boat, X: 5, Y: 5}},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The shots are fired in order; bob misses in between.
+		shots := []struct {
+			user         string
+			x, y         int32
+			result       gamepb.FireShotResponse_Result
+			event        gamepb.EventType
+			ship, winner string
+			code         gamepb.ErrorCode
+		}{
+			{user: alice, x: 7, y: 7, result: gamepb.FireShotResponse_MISS, event: gamepb.EventType_MISS},
+			{user: alice, x: 7, y: 6, code: gamepb.ErrorCode_NOT_YOUR_TURN},
+			{user: bob, x: 7, y: 7, result: gamepb.FireShotResponse_MISS, event: gamepb.EventType_MISS},
+			{user: alice, x: 0, y: 0, result: gamepb.FireShotResponse_HIT, event: gamepb.EventType_HIT},
+			{user: bob, x: 7, y: 6, result: gamepb.FireShotResponse_MISS, event: gamepb.EventType_MISS},
+			{user: alice, x: 1, y: 0, result: gamepb.FireShotResponse_SUNK, event: gamepb.EventType_HIT, ship: Destroyer},
+			{user: bob, x: 7, y: 5, result: gamepb.FireShotResponse_MISS, event: gamepb.EventType_MISS},
+			{user: alice, x: 1, y: 0, result: gamepb.FireShotResponse_ALREADY_TAKEN, event: gamepb.EventType_TAKEN},
+			{user: alice, x: 8, y: 0, code: gamepb.ErrorCode_OUT_OF_BOUNDS},
+			{user: carol, x: 5, y: 5, code: gamepb.ErrorCode_NOT_A_PLAYER},
+			{user: alice, x: 5, y: 5, result: gamepb.FireShotResponse_GAME_OVER, event: gamepb.EventType_HIT, ship: Boat, winner: alice},
+			{user: bob, x: 7, y: 6, code: gamepb.ErrorCode_GAME_FINISHED},
+		}
+		for i, shot := range shots {
+			resp, err := s.FireShot(as(shot.user), &gamepb.FireShotRequest{GameId: g.Id, X: shot.x, Y: shot.y})
+			if shot.code != gamepb.ErrorCode_ERROR_CODE_UNSPECIFIED {
+				if got := errorReason(err); got != shot.code.String() || status.Code(err) != errorStatusCodes[shot.code] {
+					t.Errorf("shot %d of %s at %d, %d: error %v with reason %q, want %s", i, shot.user, shot.x, shot.y, err, got, shot.code)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("shot %d of %s at %d, %d: %v", i, shot.user, shot.x, shot.y, err)
+			}
+			if resp.Result != shot.result || resp.Event.GetType() != shot.event || resp.ShipType != shot.ship || resp.Winner != shot.winner {
+				t.Errorf("shot %d of %s at %d, %d = %v, want %s, %s, ship %q, winner %q", i, shot.user, shot.x, shot.y, resp, shot.result, shot.event, shot.ship, shot.winner)
+			}
+		}
+	})
+}
+
+// errorReason returns the game error code in the details of a status.
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Domain == errorDomain {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+// playerStream is a PlayerMove stream fed from recv, closed by closing it.
+type playerStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	recv chan *gamepb.GameEvent
+	sent chan *gamepb.GameEvent
+}
+
+func (s *playerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *playerStream) Recv() (*gamepb.GameEvent, error) {
+	select {
+	case event, ok := <-s.recv:
+		if !ok {
+			return nil, io.EOF
+		}
+		return event, nil
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+func (s *playerStream) Send(event *gamepb.GameEvent) error {
+	s.sent <- event
+	return nil
+}
+
+func TestPlayerMoveErrors(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		s := newTestServer(t, backend)
+		g := createGame(t, s.store, ModeRealtime)
+		bobsTurn, err := s.store.CreateGame(ctx, bob, alice, DefaultRules, ModeRealtime, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		others, err := s.store.CreateGame(ctx, bob, carol, DefaultRules, ModeRealtime, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		over := createGame(t, s.store, ModeRealtime)
+		if _, err := s.store.Resign(ctx, over.Id, bob); err != nil {
+			t.Fatal(err)
+		}
+
+		stream := &playerStream{ctx: as(alice), recv: make(chan *gamepb.GameEvent), sent: make(chan *gamepb.GameEvent, 16)}
+		done := make(chan error, 1)
+		go func() { done <- s.PlayerMove(stream) }()
+
+		tests := []struct {
+			name  string
+			event *gamepb.GameEvent
+			want  gamepb.ErrorCode
+		}{
+			{name: "unknown game", event: &gamepb.GameEvent{Type: gamepb.EventType_MOVE, GameId: "no-such-game", X: 1, Y: 1}, want: gamepb.ErrorCode_GAME_NOT_FOUND},
+			{name: "off the board", event: &gamepb.GameEvent{Type: gamepb.EventType_MOVE, GameId: g.Id, X: 8, Y: 2}, want: gamepb.ErrorCode_OUT_OF_BOUNDS},
+			{name: "not her turn", event: &gamepb.GameEvent{Type: gamepb.EventType_MOVE, GameId: bobsTurn.Id, X: 1, Y: 2}, want: gamepb.ErrorCode_NOT_YOUR_TURN},
+			{name: "game of others", event: &gamepb.GameEvent{Type: gamepb.EventType_MOVE, GameId: others.Id, X: 1, Y: 2}, want: gamepb.ErrorCode_NOT_A_PLAYER},
+			{name: "game over", event: &gamepb.GameEvent{Type: gamepb.EventType_MOVE, GameId: over.Id, X: 1, Y: 2}, want: gamepb.ErrorCode_GAME_FINISHED},
+			{name: "as another user", event: &gamepb.GameEvent{Type: gamepb.EventType_MOVE, GameId: bobsTurn.Id, UserId1: bob, X: 1, Y: 2}, want: gamepb.ErrorCode_NOT_A_PLAYER},
+			{name: "subscribe to others", event: &gamepb.GameEvent{Type: gamepb.EventType_SUBSCRIBE, GameId: others.Id}, want: gamepb.ErrorCode_NOT_A_PLAYER},
+			{name: "subscribe to unknown", event: &gamepb.GameEvent{Type: gamepb.EventType_SUBSCRIBE, GameId: "no-such-game"}, want: gamepb.ErrorCode_GAME_NOT_FOUND},
+		}
+		for _, tt := range tests {
+			stream.recv <- tt.event
+			event := next(t, stream.sent)
+			if event.Type != gamepb.EventType_ERROR || event.ErrorCode != tt.want || event.ErrorMessage == "" {
+				t.Errorf("%s: received %v, want an ERROR event with %s", tt.name, event, tt.want)
+				continue
+			}
+			if event.GameId != tt.event.GameId || event.X != tt.event.X || event.Y != tt.event.Y {
+				t.Errorf("%s: error names game %q at %d, %d, want the rejected event", tt.name, event.GameId, event.X, event.Y)
+			}
+		}
+
+		// Rejected events leave the stream open and record nothing.
+		stream.recv <- &gamepb.GameEvent{Type: gamepb.EventType_MOVE, GameId: g.Id, X: 6, Y: 6}
+		if event := next(t, stream.sent); event.Type != gamepb.EventType_MISS {
+			t.Errorf("shot after the errors answered with %v, want MISS", event)
+		}
+		if moves, _ := s.store.GetMoves(ctx, bobsTurn.Id, alice); len(moves) != 0 {
+			t.Errorf("%d moves recorded for rejected shots", len(moves))
+		}
+
+		close(stream.recv)
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("PlayerMove returned %v after the client closed the stream", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("PlayerMove did not return")
+		}
+	})
+}
+
+func TestShutdown(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		s := newTestServer(t, backend)
+		stream := &playerStream{ctx: as(alice), recv: make(chan *gamepb.GameEvent), sent: make(chan *gamepb.GameEvent, 16)}
+		streamDone := make(chan error, 1)
+		go func() { streamDone <- s.PlayerMove(stream) }()
+		// The answer to an event tells that the stream is connected.
+		stream.recv <- &gamepb.GameEvent{Type: gamepb.EventType_SUBSCRIBE, GameId: "no-such-game"}
+		next(t, stream.sent)
+		// A connection whose handler is slow to leave, like a WebSocket
+		// still writing.
+		events := make(chan *gamepb.GameEvent, 16)
+		conn, err := s.Connect(bob, func(event *gamepb.GameEvent) error {
+			events <- event
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		shutdownDone := make(chan error, 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			shutdownDone <- s.Shutdown(ctx)
+		}()
+
+		for _, events := range []<-chan *gamepb.GameEvent{stream.sent, events} {
+			if event := next(t, events); event.Type != gamepb.EventType_SERVER_SHUTDOWN {
+				t.Errorf("received %v, want SERVER_SHUTDOWN", event)
+			}
+		}
+		select {
+		case err := <-streamDone:
+			if status.Code(err) != codes.Unavailable {
+				t.Errorf("PlayerMove returned %v, want Unavailable", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("PlayerMove did not return on shutdown")
+		}
+		if _, err := s.Connect(carol, func(*gamepb.GameEvent) error { return nil }); status.Code(err) != codes.Unavailable {
+			t.Errorf("Connect during shutdown: error %v, want Unavailable", err)
+		}
+
+		// Shutdown waits for the last connection.
+		select {
+		case err := <-shutdownDone:
+			t.Fatalf("Shutdown returned %v with a connection open", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+		s.Disconnect(conn)
+		select {
+		case err := <-shutdownDone:
+			if err != nil {
+				t.Errorf("Shutdown returned %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Shutdown did not return after the last connection closed")
+		}
+		if err := s.Shutdown(context.Background()); err != nil {
+			t.Errorf("second Shutdown returned %v", err)
+		}
+	})
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		s := newTestServer(t, backend)
+		conn, err := s.Connect(alice, func(*gamepb.GameEvent) error { return nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Disconnect(conn)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := s.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Shutdown with a connection left open: error %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
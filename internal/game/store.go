@@ -1,102 +1,315 @@
 package game
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	_ "modernc.org/sqlite"
+	"github.com/gosukretess/battleships/internal/database"
+	"github.com/gosukretess/battleships/internal/metrics"
+	"github.com/gosukretess/battleships/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-type Store struct {
-	db *sql.DB
-}
+var (
+	ErrCoordsTaken  = errors.New("coordinates already taken")
+	ErrGameNotFound = errors.New("game not found")
+	ErrGameOver     = errors.New("game is over")
+	ErrNotYourTurn  = errors.New("not your turn")
+	ErrInvalidOrder = errors.New(`invalid order, use "created" or "finished", optionally followed by " desc", or "turn"`)
+)
 
-func NewStore(path string) *Store {
-	db, err := sql.Open("sqlite", path)
-	if err != nil {
-		log.Fatal("Cannot open db", err)
-	}
+// Game statuses as stored in games.status.
+const (
+	StatusInProgress = "IN_PROGRESS"
+	StatusFinished   = "FINISHED"
+	StatusResigned   = "RESIGNED"
+)
 
-	createGameTable := `
-    CREATE TABLE IF NOT EXISTS games (
-        id TEXT PRIMARY KEY,
-        userid1 TEXT,
-        userid2 TEXT,
-		created TEXT,
-		nextuser TEXT
-    );`
-	if _, err := db.Exec(createGameTable); err != nil {
-		log.Fatal("cannot create game table:", err)
-	}
+// Game modes as stored in games.mode.
+const (
+	ModeRealtime = "REALTIME"
+	// ModeCorrespondence games are played over days. Players are notified
+	// when it is their turn and lose if they do not move in time.
+	ModeCorrespondence = "CORRESPONDENCE"
+)
 
-	createShipTable := `
-    CREATE TABLE IF NOT EXISTS ships (
-        gameid TEXT,
-        userid TEXT,
-        x INTEGER,
-		y INTEGER
-    );`
-	if _, err := db.Exec(createShipTable); err != nil {
-		log.Fatal("cannot create ship table:", err)
-	}
+const gameColumns = "id, userid1, userid2, created, nextuser, rules, status, winner, finished, mode, turnstarted"
 
-	createMovesTable := `
-    CREATE TABLE IF NOT EXISTS moves (
-        gameid TEXT,
-        userid TEXT,
-        x INTEGER,
-		y INTEGER,
-		hit BOOLEAN
-    );`
-	if _, err := db.Exec(createMovesTable); err != nil {
-		log.Fatal("cannot create moves table:", err)
-	}
+type Store struct {
+	db *database.DB
+}
 
+func NewStore(db *database.DB) *Store {
 	return &Store{db: db}
 }
 
-func (s *Store) CreateGame(userId1, userId2 string) (GameDto, error) {
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// instrument starts a span and a latency measurement for a store operation.
+func (s *Store) instrument(ctx context.Context, operation string) (context.Context, func()) {
+	ctx, span := tracing.Start(ctx, "game.Store/"+operation, trace.WithAttributes(
+		attribute.String("db.system", s.db.Backend),
+		attribute.String("db.operation.name", operation),
+	))
+	observe := metrics.ObserveQuery("game", operation)
+	return ctx, func() {
+		observe()
+		span.End()
+	}
+}
+
+// CreateGame stores a new game together with the fleets of both players, in
+// one transaction so that a game is never left without ships.
+func (s *Store) CreateGame(ctx context.Context, userId1, userId2, rules, mode string, fleet1, fleet2 []shipCell) (GameDto, error) {
+	ctx, done := s.instrument(ctx, "create_game")
+	defer done()
+
 	id := uuid.New().String()
 	currentTime := time.Now().UTC().Format(time.RFC3339)
 
 	gameDto := GameDto{
-		Id:       id,
-		UserId1:  userId1,
-		UserId2:  userId2,
-		Created:  currentTime,
-		NextUser: userId1,
+		Id:          id,
+		UserId1:     userId1,
+		UserId2:     userId2,
+		Created:     currentTime,
+		NextUser:    userId1,
+		Rules:       rules,
+		Status:      StatusInProgress,
+		Mode:        mode,
+		TurnStarted: currentTime,
 	}
 
-	_, err := s.db.Exec("INSERT INTO games(id, userid1, userid2, created, nextuser) VALUES (?, ?, ?, ?, ?)", gameDto.Id, gameDto.UserId1, gameDto.UserId2, gameDto.Created, gameDto.NextUser)
-	return gameDto, err
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return GameDto{}, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO games(id, userid1, userid2, created, nextuser, rules, mode, turnstarted) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		gameDto.Id, gameDto.UserId1, gameDto.UserId2, gameDto.Created, gameDto.NextUser, gameDto.Rules, gameDto.Mode, gameDto.TurnStarted)
+	if err != nil {
+		return GameDto{}, err
+	}
+	if err := s.addShips(ctx, tx, id, userId1, fleet1); err != nil {
+		return GameDto{}, err
+	}
+	if err := s.addShips(ctx, tx, id, userId2, fleet2); err != nil {
+		return GameDto{}, err
+	}
+	return gameDto, tx.Commit()
 }
 
-func (s *Store) AddShip(gameId, userId string, x, y int) {
-	s.db.Exec("INSERT INTO ships(gameid, userid, x, y) VALUES (?, ?, ?, ?)", gameId, userId, x, y)
+func (s *Store) addShips(ctx context.Context, tx *database.Tx, gameId, userId string, cells []shipCell) error {
+	for _, cell := range cells {
+		_, err := tx.ExecContext(ctx, "INSERT INTO ships(gameid, userid, ship, type, x, y) VALUES (?, ?, ?, ?, ?, ?)",
+			gameId, userId, cell.Ship, cell.Type, cell.X, cell.Y)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (s *Store) GetGames() ([]GameDto, error) {
-	rows, err := s.db.Query("SELECT id, userid1, userid2, created, nextuser FROM games")
+func (s *Store) GetGames(ctx context.Context) ([]GameDto, error) {
+	ctx, done := s.instrument(ctx, "get_games")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+gameColumns+" FROM games")
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var users []GameDto
+	var games []GameDto
 	for rows.Next() {
-		var u GameDto
-		if err := rows.Scan(&u.Id, &u.UserId1, &u.UserId2, &u.Created, &u.NextUser); err != nil {
+		g, err := scanGame(rows)
+		if err != nil {
 			return nil, err
 		}
-		users = append(users, u)
+		games = append(games, g)
+	}
+	return games, nil
+}
+
+// GameFilter selects games for ListGames. Empty fields match every game.
+type GameFilter struct {
+	UserId string
+	// NextUser only matches games waiting for this user to move.
+	NextUser      string
+	Status        string
+	Rules         string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// gameOrders are the orderings ListGames accepts. Unfinished games have no
+// finish time and come last when ordered by it.
+var gameOrders = map[string]string{
+	"created":       "created, id",
+	"created desc":  "created DESC, id DESC",
+	"finished":      "finished IS NULL, finished, id",
+	"finished desc": "finished IS NULL, finished DESC, id DESC",
+	// The games waiting longest for a move first.
+	"turn": "turnstarted, id",
+}
+
+// DefaultGameOrder lists the newest games first.
+const DefaultGameOrder = "created desc"
+
+// ListGames returns one page of the games matching filter, in one of the
+// gameOrders. The second result is the offset of the next page, or 0 on the
+// last page. It returns ErrInvalidOrder for an unknown order.
+func (s *Store) ListGames(ctx context.Context, filter GameFilter, order string, offset, limit int) ([]GameDto, int, error) {
+	ctx, done := s.instrument(ctx, "list_games")
+	defer done()
+
+	orderBy, ok := gameOrders[order]
+	if !ok {
+		return nil, 0, ErrInvalidOrder
+	}
+
+	var where []string
+	var args []any
+	if filter.UserId != "" {
+		where = append(where, "(userid1 = ? OR userid2 = ?)")
+		args = append(args, filter.UserId, filter.UserId)
+	}
+	if filter.NextUser != "" {
+		where = append(where, "nextuser = ?")
+		args = append(args, filter.NextUser)
+	}
+	if filter.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.Rules != "" {
+		where = append(where, "rules = ?")
+		args = append(args, filter.Rules)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		where = append(where, "created >= ?")
+		args = append(args, filter.CreatedAfter.UTC().Format(time.RFC3339))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		where = append(where, "created < ?")
+		args = append(args, filter.CreatedBefore.UTC().Format(time.RFC3339))
+	}
+
+	query := "SELECT " + gameColumns + " FROM games"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	// One more row than asked for tells whether there is a next page.
+	query += " ORDER BY " + orderBy + " LIMIT ? OFFSET ?"
+	args = append(args, limit+1, offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var games []GameDto
+	for rows.Next() {
+		g, err := scanGame(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		games = append(games, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if len(games) > limit {
+		return games[:limit], offset + limit, nil
+	}
+	return games, 0, nil
+}
+
+func (s *Store) GetGame(ctx context.Context, id string) (GameDto, error) {
+	ctx, done := s.instrument(ctx, "get_game")
+	defer done()
+
+	g, err := scanGame(s.db.QueryRowContext(ctx, "SELECT "+gameColumns+" FROM games WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return g, ErrGameNotFound
+	}
+	return g, err
+}
+
+// Resign ends a game in progress with winner as the winner and updates the
+// ratings of both players. It returns ErrGameOver if the game has already ended.
+func (s *Store) Resign(ctx context.Context, gameId, winner string) (string, error) {
+	ctx, done := s.instrument(ctx, "resign")
+	defer done()
+
+	return s.endGame(ctx, gameId, winner, true, "")
+}
+
+// Abandon ends a game in progress with winner as the winner, like Resign, but
+// leaves the ratings alone. It is for games whose other player deleted their
+// account. It returns ErrGameOver if the game has already ended.
+func (s *Store) Abandon(ctx context.Context, gameId, winner string) (string, error) {
+	ctx, done := s.instrument(ctx, "abandon")
+	defer done()
+
+	return s.endGame(ctx, gameId, winner, false, "")
+}
+
+// Forfeit ends a game as resigned by the player whose turn timed out, unless
+// they have moved since game was read. It returns ErrGameOver if the game has
+// ended or the turn has passed.
+func (s *Store) Forfeit(ctx context.Context, game GameDto) (string, error) {
+	ctx, done := s.instrument(ctx, "forfeit")
+	defer done()
+
+	winner, _ := game.Opponent(game.NextUser)
+	return s.endGame(ctx, game.Id, winner, true, " AND nextuser = ? AND turnstarted = ?", game.NextUser, game.TurnStarted)
+}
+
+// endGame marks a game in progress that also matches the extra conditions as
+// resigned and, if rated, updates the ratings, in one transaction.
+func (s *Store) endGame(ctx context.Context, gameId, winner string, rated bool, extra string, args ...any) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	finished := time.Now().UTC().Format(time.RFC3339)
+	result, err := tx.ExecContext(ctx, "UPDATE games SET status = ?, winner = ?, finished = ? WHERE id = ? AND status = ?"+extra,
+		append([]any{StatusResigned, winner, finished, gameId, StatusInProgress}, args...)...)
+	if err != nil {
+		return "", err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+	if n == 0 {
+		return "", ErrGameOver
+	}
+	if rated {
+		if err := s.updateRatings(ctx, tx, gameId, winner); err != nil {
+			return "", err
+		}
 	}
-	return users, nil
+	return finished, tx.Commit()
 }
 
-func (s *Store) GetShips(gameId, userId string) ([]ShipDto, error) {
-	rows, err := s.db.Query("SELECT gameid, userid, x, y FROM ships WHERE gameid = ? AND userid = ?",
+func (s *Store) GetShips(ctx context.Context, gameId, userId string) ([]ShipDto, error) {
+	ctx, done := s.instrument(ctx, "get_ships")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT gameid, userid, type, x, y FROM ships WHERE gameid = ? AND userid = ?",
 		gameId, userId,
 	)
 	if err != nil {
@@ -107,7 +320,7 @@ func (s *Store) GetShips(gameId, userId string) ([]ShipDto, error) {
 	var ships []ShipDto
 	for rows.Next() {
 		var ship ShipDto
-		if err := rows.Scan(&ship.GameId, &ship.UserId, &ship.X, &ship.Y); err != nil {
+		if err := rows.Scan(&ship.GameId, &ship.UserId, &ship.Type, &ship.X, &ship.Y); err != nil {
 			return nil, err
 		}
 		ships = append(ships, ship)
@@ -115,8 +328,11 @@ func (s *Store) GetShips(gameId, userId string) ([]ShipDto, error) {
 	return ships, nil
 }
 
-func (s *Store) GetMoves(gameId, userId string) ([]MoveDto, error) {
-	rows, err := s.db.Query("SELECT gameid, userid, x, y, hit FROM moves WHERE gameid = ? AND userid = ?",
+func (s *Store) GetMoves(ctx context.Context, gameId, userId string) ([]MoveDto, error) {
+	ctx, done := s.instrument(ctx, "get_moves")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT gameid, userid, x, y, hit FROM moves WHERE gameid = ? AND userid = ? ORDER BY seq",
 		gameId, userId,
 	)
 	if err != nil {
@@ -135,53 +351,188 @@ func (s *Store) GetMoves(gameId, userId string) ([]MoveDto, error) {
 	return moves, nil
 }
 
-func (s *Store) AreCoordsTaken(gameId, userId string, x, y int) (bool, error) {
-	query := "SELECT 1 FROM moves WHERE gameid = ? AND userid = ? AND x = ? AND y = ? LIMIT 1"
-	var exists int
-	err := s.db.QueryRow(query, gameId, userId, x, y).Scan(&exists)
-	if err == sql.ErrNoRows {
-		return false, err
+// Move records a shot and passes the turn to the opponent in one transaction.
+// A hit on the last ship of the opponent also ends the game. It returns
+// ErrCoordsTaken if the user has already shot at these coordinates,
+// ErrNotYourTurn if it is the opponent's turn and ErrGameOver if the game has
+// ended.
+func (s *Store) Move(ctx context.Context, gameId, userId string, x, y int) (MoveResult, error) {
+	ctx, done := s.instrument(ctx, "move")
+	defer done()
+
+	var result MoveResult
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, err
 	}
-	return true, nil
-}
+	defer tx.Rollback()
 
-func (s *Store) Move(gameId, userId string, x, y int) (bool, error) {
-	query := "SELECT 1 FROM ships WHERE gameid = ? AND userid <> ? AND x = ? AND y = ? LIMIT 1"
-	var exists int
-	err := s.db.QueryRow(query, gameId, userId, x, y).Scan(&exists)
-	if err != nil && err != sql.ErrNoRows {
-		return false, err
+	var status, nextUser string
+	err = tx.QueryRowContext(ctx, "SELECT status, nextuser FROM games WHERE id = ?", gameId).Scan(&status, &nextUser)
+	if err == sql.ErrNoRows {
+		return result, ErrGameNotFound
 	}
-	hit := exists == 1
-	insert := `INSERT INTO moves (gameid, userid, x, y, hit) VALUES (?, ?, ?, ?, ?)`
-	_, err = s.db.Exec(insert, gameId, userId, x, y, hit)
 	if err != nil {
-		return hit, err
+		return result, err
+	}
+	if status != StatusInProgress {
+		return result, ErrGameOver
+	}
+	if nextUser != userId {
+		return result, ErrNotYourTurn
 	}
 
+	// Passing the turn first locks the game row until the transaction ends.
+	// A concurrent move in the same game waits for it and then finds that the
+	// turn has passed, so the number of the shot below cannot be taken twice.
 	updateQuery := `
 		UPDATE games 
 		SET nextuser = CASE 
 			WHEN userid1 = ? THEN userid2 
 			WHEN userid2 = ? THEN userid1 
 			ELSE nextuser 
-		END
-		WHERE id = ?`
-	_, err = s.db.Exec(updateQuery, userId, userId, gameId)
+		END,
+		turnstarted = ?
+		WHERE id = ? AND nextuser = ?`
+	updated, err := tx.ExecContext(ctx, updateQuery, userId, userId, time.Now().UTC().Format(time.RFC3339), gameId, userId)
+	if err != nil {
+		return result, err
+	}
+	n, err := updated.RowsAffected()
 	if err != nil {
-		return hit, err
+		return result, err
+	}
+	if n == 0 {
+		return result, ErrNotYourTurn
 	}
 
-	return hit, nil
+	query := "SELECT ship, type FROM ships WHERE gameid = ? AND userid <> ? AND x = ? AND y = ? LIMIT 1"
+	var ship sql.NullInt64
+	var shipType string
+	err = tx.QueryRowContext(ctx, query, gameId, userId, x, y).Scan(&ship, &shipType)
+	if err != nil && err != sql.ErrNoRows {
+		return result, err
+	}
+	result.Hit = err == nil
+
+	insert := `
+		INSERT INTO moves (gameid, userid, x, y, hit, seq)
+		VALUES (?, ?, ?, ?, ?, (SELECT COALESCE(MAX(seq), 0) + 1 FROM moves WHERE gameid = ?))`
+	_, err = tx.ExecContext(ctx, insert, gameId, userId, x, y, result.Hit, gameId)
+	if database.IsUniqueViolation(err) {
+		return MoveResult{}, ErrCoordsTaken
+	}
+	if err != nil {
+		return result, err
+	}
+
+	if result.Hit {
+		sunk, err := s.isSunk(ctx, tx, gameId, userId, ship)
+		if err != nil {
+			return result, err
+		}
+		if sunk {
+			result.Sunk = shipType
+		}
+		result.Won, err = s.finishIfSunk(ctx, tx, gameId, userId)
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, tx.Commit()
+}
+
+// isSunk tells whether userId has hit every cell of a ship of the opponent.
+// Ships without a number have a single cell.
+func (s *Store) isSunk(ctx context.Context, tx *database.Tx, gameId, userId string, ship sql.NullInt64) (bool, error) {
+	if !ship.Valid {
+		return true, nil
+	}
+	var afloat int
+	err := tx.QueryRowContext(ctx, `
+		SELECT count(*) FROM ships s
+		WHERE s.gameid = ? AND s.userid <> ? AND s.ship = ?
+		AND NOT EXISTS (SELECT 1 FROM moves m WHERE m.gameid = s.gameid AND m.userid = ? AND m.x = s.x AND m.y = s.y)`,
+		gameId, userId, ship.Int64, userId).Scan(&afloat)
+	return afloat == 0, err
+}
+
+// finishIfSunk ends the game with userId as the winner once they have hit
+// every ship of the opponent, and updates the ratings of both players.
+func (s *Store) finishIfSunk(ctx context.Context, tx *database.Tx, gameId, userId string) (bool, error) {
+	var hits, ships int
+	err := tx.QueryRowContext(ctx, `
+		SELECT
+			(SELECT count(*) FROM moves WHERE gameid = ? AND userid = ? AND hit),
+			(SELECT count(*) FROM ships WHERE gameid = ? AND userid <> ?)`,
+		gameId, userId, gameId, userId).Scan(&hits, &ships)
+	if err != nil || hits < ships {
+		return false, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE games SET status = ?, winner = ?, finished = ? WHERE id = ?",
+		StatusFinished, userId, time.Now().UTC().Format(time.RFC3339), gameId)
+	if err != nil {
+		return false, err
+	}
+	return true, s.updateRatings(ctx, tx, gameId, userId)
+}
+
+// ExpiredTurns returns the correspondence games in progress whose current
+// turn started before cutoff.
+func (s *Store) ExpiredTurns(ctx context.Context, cutoff time.Time) ([]GameDto, error) {
+	ctx, done := s.instrument(ctx, "expired_turns")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+gameColumns+" FROM games WHERE mode = ? AND status = ? AND turnstarted < ?",
+		ModeCorrespondence, StatusInProgress, cutoff.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []GameDto
+	for rows.Next() {
+		g, err := scanGame(rows)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, g)
+	}
+	return games, rows.Err()
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanGame(row scanner) (GameDto, error) {
+	var g GameDto
+	var winner, finished, turnStarted sql.NullString
+	err := row.Scan(&g.Id, &g.UserId1, &g.UserId2, &g.Created, &g.NextUser, &g.Rules, &g.Status, &winner, &finished, &g.Mode, &turnStarted)
+	g.Winner = winner.String
+	g.Finished = finished.String
+	g.TurnStarted = turnStarted.String
+	return g, err
 }
 
 type ShipDto struct {
 	GameId string
 	UserId string
+	Type   string
 	X      int
 	Y      int
 }
 
+type MoveResult struct {
+	Hit bool
+	// Sunk is the type of the ship the shot sank, if it did.
+	Sunk string
+	// Won is set when the shot sank the last ship of the opponent.
+	Won bool
+}
+
 type MoveDto struct {
 	GameId string
 	UserId string
@@ -196,4 +547,23 @@ type GameDto struct {
 	UserId2  string
 	Created  string
 	NextUser string
+	Rules    string
+	Status   string
+	// Winner and Finished are empty while the game is in progress.
+	Winner   string
+	Finished string
+	Mode     string
+	// TurnStarted is when NextUser got the turn.
+	TurnStarted string
+}
+
+// Opponent returns the other player of the game, or false if userId does not play in it.
+func (g GameDto) Opponent(userId string) (string, bool) {
+	switch userId {
+	case g.UserId1:
+		return g.UserId2, true
+	case g.UserId2:
+		return g.UserId1, true
+	}
+	return "", false
 }
@@ -0,0 +1,390 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/gosukretess/battleships/internal/database"
+	"github.com/gosukretess/battleships/internal/database/dbtest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMain(m *testing.M) {
+	dbtest.Main(m)
+}
+
+const (
+	alice = "alice"
+	bob   = "bob"
+)
+
+func newTestStore(t *testing.T, backend string) *Store {
+	t.Helper()
+
+	db := dbtest.New(t, backend)
+	for _, id := range []string{alice, bob} {
+		if _, err := db.Exec("INSERT INTO users(id, name, email) VALUES (?, ?, ?)", id, id, id+"@example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewStore(db)
+}
+
+// createGame creates a game of alice against bob. Alice has a boat at (3, 3)
+// and bob a destroyer at (0, 0) and (1, 0).
+func createGame(t *testing.T, store *Store, mode string) GameDto {
+	t.Helper()
+
+	g, err := store.CreateGame(context.Background(), alice, bob, DefaultRules, mode,
+		[]shipCell{{Ship: 0, Type: Boat, X: 3, Y: 3}},
+		[]shipCell{{Ship: 0, Type: Destroyer, X: 0, Y: 0}, {Ship: 0, Type: Destroyer, X: 1, Y: 0}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestStoreMove(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		store := newTestStore(t, backend)
+		g := createGame(t, store, ModeRealtime)
+
+		shots := []struct {
+			user string
+			x, y int
+			want MoveResult
+			err  error
+		}{
+			{user: bob, x: 3, y: 3, err: ErrNotYourTurn},
+			{user: alice, x: 5, y: 5},
+			{user: alice, x: 0, y: 0, err: ErrNotYourTurn},
+			{user: bob, x: 5, y: 5},
+			{user: alice, x: 5, y: 5, err: ErrCoordsTaken},
+			{user: alice, x: 0, y: 0, want: MoveResult{Hit: true}},
+			{user: bob, x: 6, y: 6},
+			{user: alice, x: 1, y: 0, want: MoveResult{Hit: true, Sunk: Destroyer, Won: true}},
+			{user: bob, x: 3, y: 3, err: ErrGameOver},
+		}
+		for _, shot := range shots {
+			got, err := store.Move(ctx, g.Id, shot.user, shot.x, shot.y)
+			if !errors.Is(err, shot.err) {
+				t.Fatalf("%s at (%d, %d): error %v, want %v", shot.user, shot.x, shot.y, err, shot.err)
+			}
+			if got != shot.want {
+				t.Fatalf("%s at (%d, %d) = %+v, want %+v", shot.user, shot.x, shot.y, got, shot.want)
+			}
+		}
+
+		g, err := store.GetGame(ctx, g.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.Status != StatusFinished || g.Winner != alice || g.Finished == "" {
+			t.Errorf("game after the last ship sank: status %s, winner %q, finished %q", g.Status, g.Winner, g.Finished)
+		}
+
+		moves, err := store.GetMoves(ctx, g.Id, alice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantMoves := []MoveDto{
+			{GameId: g.Id, UserId: alice, X: 5, Y: 5},
+			{GameId: g.Id, UserId: alice, X: 0, Y: 0, Hit: true},
+			{GameId: g.Id, UserId: alice, X: 1, Y: 0, Hit: true},
+		}
+		if len(moves) != len(wantMoves) {
+			t.Fatalf("alice has %d moves, want %d", len(moves), len(wantMoves))
+		}
+		for i := range moves {
+			if moves[i] != wantMoves[i] {
+				t.Errorf("move %d = %+v, want %+v", i, moves[i], wantMoves[i])
+			}
+		}
+
+		leaders, _, err := store.GetLeaderboard(ctx, DefaultRules, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(leaders) != 2 || leaders[0].UserId != alice || leaders[0].Rating <= leaders[1].Rating {
+			t.Errorf("leaderboard after alice won = %+v", leaders)
+		}
+
+		if _, err := store.Move(ctx, "no-such-game", alice, 0, 0); !errors.Is(err, ErrGameNotFound) {
+			t.Errorf("move in an unknown game: error %v, want %v", err, ErrGameNotFound)
+		}
+	})
+}
+
+func TestStoreListGames(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		store := newTestStore(t, backend)
+		var ids []string
+		for range 3 {
+			ids = append(ids, createGame(t, store, ModeRealtime).Id)
+		}
+		if _, err := store.Resign(ctx, ids[0], bob); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Resign(ctx, ids[0], bob); !errors.Is(err, ErrGameOver) {
+			t.Errorf("second resignation: error %v, want %v", err, ErrGameOver)
+		}
+
+		page, next, err := store.ListGames(ctx, GameFilter{UserId: alice}, "created", 0, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) != 2 || next != 2 {
+			t.Errorf("first page has %d games and next offset %d, want 2 and 2", len(page), next)
+		}
+		page, next, err = store.ListGames(ctx, GameFilter{UserId: alice}, "created", next, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) != 1 || next != 0 {
+			t.Errorf("last page has %d games and next offset %d, want 1 and 0", len(page), next)
+		}
+
+		finished, _, err := store.ListGames(ctx, GameFilter{Status: StatusResigned}, DefaultGameOrder, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(finished) != 1 || finished[0].Id != ids[0] || finished[0].Winner != bob {
+			t.Errorf("resigned games = %+v, want only %s won by bob", finished, ids[0])
+		}
+
+		waiting, _, err := store.ListGames(ctx, GameFilter{NextUser: alice, Status: StatusInProgress}, "turn", 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(waiting) != 2 {
+			t.Errorf("%d games wait for alice, want 2", len(waiting))
+		}
+
+		if _, _, err := store.ListGames(ctx, GameFilter{}, "name", 0, 10); !errors.Is(err, ErrInvalidOrder) {
+			t.Errorf("unknown order: error %v, want %v", err, ErrInvalidOrder)
+		}
+	})
+}
+
+func TestStoreForfeit(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		store := newTestStore(t, backend)
+		realtime := createGame(t, store, ModeRealtime)
+		g := createGame(t, store, ModeCorrespondence)
+
+		expired, err := store.ExpiredTurns(ctx, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(expired) != 1 || expired[0].Id != g.Id {
+			t.Fatalf("expired turns = %+v, want only the correspondence game %s", expired, g.Id)
+		}
+		if expired, _ := store.ExpiredTurns(ctx, time.Now().Add(-time.Hour)); len(expired) != 0 {
+			t.Errorf("turns expired before the game was created: %+v", expired)
+		}
+
+		if _, err := store.Forfeit(ctx, expired[0]); err != nil {
+			t.Fatal(err)
+		}
+		g, err = store.GetGame(ctx, g.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.Status != StatusResigned || g.Winner != bob {
+			t.Errorf("forfeited game: status %s, winner %q, want %s won by bob", g.Status, g.Winner, StatusResigned)
+		}
+		if _, err := store.Forfeit(ctx, expired[0]); !errors.Is(err, ErrGameOver) {
+			t.Errorf("second forfeit: error %v, want %v", err, ErrGameOver)
+		}
+
+		// A move since the game was read keeps the turn from being forfeited.
+		if _, err := store.Move(ctx, realtime.Id, alice, 7, 7); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Forfeit(ctx, realtime); !errors.Is(err, ErrGameOver) {
+			t.Errorf("forfeit after a move: error %v, want %v", err, ErrGameOver)
+		}
+	})
+}
+
+func TestStoreCreateGame(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		store := newTestStore(t, backend)
+		g := createGame(t, store, ModeRealtime)
+
+		for user, want := range map[string]int{alice: 1, bob: 2} {
+			ships, err := store.GetShips(ctx, g.Id, user)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ships) != want {
+				t.Errorf("%s has %d ship cells, want %d", user, len(ships), want)
+			}
+		}
+
+		// Without anywhere to put the ships the game is not created either.
+		if _, err := store.db.Exec("DROP TABLE ships"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.CreateGame(ctx, alice, bob, DefaultRules, ModeRealtime, []shipCell{{Type: Boat}}, []shipCell{{Type: Boat}}); err == nil {
+			t.Fatal("CreateGame succeeded without a ships table")
+		}
+		games, err := store.GetGames(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(games) != 1 {
+			t.Errorf("%d games after a failed CreateGame, want 1", len(games))
+		}
+	})
+}
+
+func TestFireDeletedGame(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		s := NewServer(newTestStore(t, backend), nil, nil, nil, Config{})
+		game := GameDto{Id: "deleted", UserId1: alice, UserId2: bob, NextUser: alice, Status: StatusInProgress}
+
+		_, _, err := s.fire(context.Background(), slog.New(slog.DiscardHandler), game, alice, 0, 0)
+		if code := status.Code(err); code != codes.NotFound {
+			t.Errorf("shot at a deleted game: error %v, want NotFound", err)
+		}
+	})
+}
+
+func TestStoreRatingsConcurrent(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		store := newTestStore(t, backend)
+
+		const games = 8
+		ids := make([]string, games)
+		for i := range ids {
+			ids[i] = createGame(t, store, ModeRealtime).Id
+		}
+		errs := make(chan error, games)
+		for _, id := range ids {
+			go func() {
+				_, err := store.Resign(ctx, id, alice)
+				errs <- err
+			}()
+		}
+		for range games {
+			if err := <-errs; err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, user := range []string{alice, bob} {
+			history, err := store.GetRatingHistory(ctx, user, DefaultRules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(history) != games {
+				t.Fatalf("%s has %d rating changes, want %d", user, len(history), games)
+			}
+			// Every game starts from the rating the one before it ended with,
+			// in whatever order they were committed.
+			before := map[int]int{}
+			for _, c := range history {
+				before[c.RatingBefore]++
+			}
+			rating := InitialRating
+			for range history {
+				if before[rating] != 1 {
+					t.Fatalf("rating history of %s does not chain up: %+v", user, history)
+				}
+				for _, c := range history {
+					if c.RatingBefore == rating {
+						rating = c.RatingAfter
+						break
+					}
+				}
+			}
+
+			leaders, _, err := store.GetLeaderboard(ctx, DefaultRules, 0, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range leaders {
+				if r.UserId == user && (r.Rating != rating || r.Games != games) {
+					t.Errorf("rating of %s = %d after %d games, want %d after %d", user, r.Rating, r.Games, rating, games)
+				}
+			}
+		}
+	})
+}
+
+func TestStoreMoveConcurrent(t *testing.T) {
+	tests := []struct {
+		name  string
+		cells [][2]int
+	}{
+		{name: "same cell", cells: [][2]int{{5, 5}, {5, 5}, {5, 5}, {5, 5}}},
+		{name: "different cells", cells: [][2]int{{4, 4}, {5, 5}, {6, 6}, {7, 7}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbtest.Run(t, func(t *testing.T, backend string) {
+				ctx := context.Background()
+				store := newTestStore(t, backend)
+				g := createGame(t, store, ModeRealtime)
+
+				errs := make(chan error, len(tt.cells))
+				for _, cell := range tt.cells {
+					go func() {
+						_, err := store.Move(ctx, g.Id, alice, cell[0], cell[1])
+						errs <- err
+					}()
+				}
+				moved := 0
+				for range tt.cells {
+					switch err := <-errs; {
+					case err == nil:
+						moved++
+					case errors.Is(err, ErrNotYourTurn), errors.Is(err, ErrCoordsTaken):
+					default:
+						t.Fatal(err)
+					}
+				}
+				if moved != 1 {
+					t.Errorf("%d moves in one turn succeeded, want 1", moved)
+				}
+
+				moves, err := store.GetMoves(ctx, g.Id, alice)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(moves) != 1 {
+					t.Errorf("alice has %d moves, want 1", len(moves))
+				}
+				if g, err := store.GetGame(ctx, g.Id); err != nil || g.NextUser != bob {
+					t.Errorf("next user after the turn = %q, %v, want %s", g.NextUser, err, bob)
+				}
+			})
+		})
+	}
+}
+
+func TestStoreMoveOrderUnique(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		store := newTestStore(t, backend)
+		g := createGame(t, store, ModeRealtime)
+		if _, err := store.Move(context.Background(), g.Id, alice, 5, 5); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := store.db.Exec("INSERT INTO moves (gameid, userid, x, y, hit, seq) VALUES (?, ?, 6, 6, false, 1)", g.Id, bob)
+		if !database.IsUniqueViolation(err) {
+			t.Errorf("second shot numbered 1: error %v, want a unique violation", err)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package gateway
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"errors"
+	"net"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/gosukretess/battleships/internal/logging"
+	"github.com/gosukretess/battleships/internal/tlsutil"
+	"github.com/gosukretess/battleships/proto/gamepb"
+	"github.com/gosukretess/battleships/proto/userpb"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// The spec is generated together with the gateway handlers from
+// proto/game.proto and proto/user.proto.
+//
+//go:embed battleships.swagger.json
+var openAPISpec []byte
+
+// Calls forwarded by the gateway carry the address of the HTTP client in
+// clientIPKey, with gatewayToken in gatewayTokenKey to show that the gateway
+// put it there. headerMatcher keeps HTTP clients from setting either.
+const (
+	clientIPKey     = "x-battleships-client-ip"
+	gatewayTokenKey = "x-battleships-gateway-token"
+)
+
+// gatewayToken is made up at startup, so only the gateway of this process
+// knows it.
+var gatewayToken = rand.Text()
+
+type Config struct {
+	// Listen is the address of the HTTP/JSON gateway. Empty disables it.
+	Listen string `yaml:"listen" toml:"listen"`
+	// TLS is used to reach the gRPC server when it only accepts TLS.
+	TLS tlsutil.ClientConfig `yaml:"tls" toml:"tls"`
+}
+
+// Gateway translates REST calls into calls to the gRPC server. It talks to the
+// server over a regular connection so that requests go through the same
+// interceptors as native gRPC clients.
+type Gateway struct {
+	conn *grpc.ClientConn
+	mux  *http.ServeMux
+}
+
+// New connects to the gRPC server at grpcAddr and registers the unary RPCs of
+// UserService and GameService under /v1. The OpenAPI spec is served at /openapi.json.
+func New(grpcAddr string, cfg Config) (*Gateway, error) {
+	creds := insecure.NewCredentials()
+	if cfg.TLS.IsEnabled() {
+		tlsConfig, err := cfg.TLS.TLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(dialAddress(grpcAddr),
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	gwMux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithMetadata(forwardClientIP),
+	)
+	ctx := context.Background()
+	if err := userpb.RegisterUserServiceHandler(ctx, gwMux, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := gamepb.RegisterGameServiceHandler(ctx, gwMux, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/", gwMux)
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPISpec)
+	})
+	return &Gateway{conn: conn, mux: mux}, nil
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+func (g *Gateway) Close() error {
+	return g.conn.Close()
+}
+
+func (c Config) Validate() error {
+	if c.Listen == "" {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(c.Listen); err != nil {
+		return errors.New("gateway listen address must be host:port")
+	}
+	return c.TLS.Validate()
+}
+
+// headerMatcher forwards the request id header in addition to the headers
+// the gateway passes on by default. Headers that would set the client address
+// the gateway forwards are dropped.
+func headerMatcher(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == textproto.CanonicalMIMEHeaderKey(logging.RequestIDHeader) {
+		return logging.RequestIDHeader, true
+	}
+	key, ok := runtime.DefaultHeaderMatcher(key)
+	if strings.EqualFold(key, clientIPKey) || strings.EqualFold(key, gatewayTokenKey) {
+		return "", false
+	}
+	return key, ok
+}
+
+// forwardClientIP passes the address the HTTP request came from to the server.
+// X-Forwarded-For is not trusted, clients can send any value in it.
+func forwardClientIP(ctx context.Context, r *http.Request) metadata.MD {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return metadata.Pairs(clientIPKey, host, gatewayTokenKey, gatewayToken)
+}
+
+// ClientIP returns the address of the HTTP client of a call forwarded by the
+// gateway of this process. Calls from anywhere else return false, whatever
+// metadata they carry.
+func ClientIP(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	tokens, ips := md.Get(gatewayTokenKey), md.Get(clientIPKey)
+	if len(tokens) != 1 || len(ips) != 1 || subtle.ConstantTimeCompare([]byte(tokens[0]), []byte(gatewayToken)) != 1 {
+		return "", false
+	}
+	return ips[0], true
+}
+
+// dialAddress turns a listen address such as ":50051" into one that can be dialed.
+func dialAddress(listen string) string {
+	host, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		return listen
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -0,0 +1,82 @@
+package healthcheck
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gosukretess/battleships/internal/game"
+	"github.com/gosukretess/battleships/internal/user"
+	"github.com/gosukretess/battleships/proto/gamepb"
+	"github.com/gosukretess/battleships/proto/userpb"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	checkInterval = 10 * time.Second
+	checkTimeout  = 2 * time.Second
+)
+
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Checker reports each service as SERVING only while its store can reach the
+// database. The empty service name stands for the server as a whole.
+type Checker struct {
+	server   *health.Server
+	services map[string]Pinger
+}
+
+func NewChecker(userStore *user.Store, gameStore *game.Store) *Checker {
+	return &Checker{
+		server: health.NewServer(),
+		services: map[string]Pinger{
+			userpb.UserService_ServiceDesc.ServiceName: userStore,
+			gamepb.GameService_ServiceDesc.ServiceName: gameStore,
+		},
+	}
+}
+
+func (c *Checker) Server() healthpb.HealthServer {
+	return c.server
+}
+
+// Run checks all services right away and then periodically until ctx is done.
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		c.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shutdown reports every service as NOT_SERVING from now on.
+func (c *Checker) Shutdown() {
+	c.server.Shutdown()
+}
+
+func (c *Checker) check(ctx context.Context) {
+	overall := healthpb.HealthCheckResponse_SERVING
+	for name, pinger := range c.services {
+		pingCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		err := pinger.Ping(pingCtx)
+		cancel()
+
+		status := healthpb.HealthCheckResponse_SERVING
+		if err != nil {
+			slog.Warn("service is not serving", "service", name, "error", err)
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			overall = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		c.server.SetServingStatus(name, status)
+	}
+	c.server.SetServingStatus("", overall)
+}
@@ -0,0 +1,54 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// pinger fails while err is set.
+type pinger struct {
+	err error
+}
+
+func (p *pinger) Ping(ctx context.Context) error {
+	return p.err
+}
+
+func TestCheck(t *testing.T) {
+	users, games := &pinger{}, &pinger{}
+	c := &Checker{server: health.NewServer(), services: map[string]Pinger{"users": users, "games": games}}
+	serving, notServing := healthpb.HealthCheckResponse_SERVING, healthpb.HealthCheckResponse_NOT_SERVING
+
+	steps := []struct {
+		name                              string
+		usersErr, gamesErr                error
+		overall, usersStatus, gamesStatus healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{name: "healthy", overall: serving, usersStatus: serving, gamesStatus: serving},
+		{name: "games down", gamesErr: errors.New("database is locked"), overall: notServing, usersStatus: serving, gamesStatus: notServing},
+		{name: "recovered", overall: serving, usersStatus: serving, gamesStatus: serving},
+	}
+	for _, step := range steps {
+		users.err, games.err = step.usersErr, step.gamesErr
+		c.check(context.Background())
+		for service, want := range map[string]healthpb.HealthCheckResponse_ServingStatus{"": step.overall, "users": step.usersStatus, "games": step.gamesStatus} {
+			resp, err := c.Server().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Status != want {
+				t.Errorf("%s: service %q is %s, want %s", step.name, service, resp.Status, want)
+			}
+		}
+	}
+
+	c.Shutdown()
+	c.check(context.Background())
+	if resp, _ := c.Server().Check(context.Background(), &healthpb.HealthCheckRequest{}); resp.Status != notServing {
+		t.Errorf("after Shutdown the server is %s, want %s", resp.Status, notServing)
+	}
+}
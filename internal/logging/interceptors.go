@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDHeader carries the correlation id between client and server. The
+// server reuses the id sent by the client, or makes one up, and echoes it back
+// in the response header.
+const RequestIDHeader = "x-request-id"
+
+// maxRequestIDLength fits a UUID with room to spare. Longer ids, and ids with
+// characters other than letters, digits, '-', '_', '.' and ':', are replaced
+// so that clients cannot write arbitrary content into the logs.
+const maxRequestIDLength = 64
+
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx, logger := newRequestLogger(ctx, info.FullMethod)
+		logger = logger.With(requestAttrs(req)...)
+		ctx = WithLogger(ctx, logger)
+
+		resp, err := handler(ctx, req)
+		logFinished(ctx, start, err)
+		return resp, err
+	}
+}
+
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, logger := newRequestLogger(stream.Context(), info.FullMethod)
+		ctx = WithLogger(ctx, logger)
+		logger.InfoContext(ctx, "stream started")
+
+		err := handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+		logFinished(ctx, start, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor and StreamClientInterceptor tag outgoing calls with a
+// fresh request id unless the context already carries one.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingRequestID(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingRequestID(ctx), desc, cc, method, opts...)
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func newRequestLogger(ctx context.Context, method string) (context.Context, *slog.Logger) {
+	requestID := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDHeader); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	if !ValidRequestID(requestID) {
+		requestID = uuid.New().String()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, requestID))
+
+	logger := slog.Default().With("request_id", requestID, "method", method)
+	return ctx, logger
+}
+
+// ValidRequestID reports whether a request id sent by a client can be logged
+// as it is.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// logFinished logs with the logger in ctx, which has the caller once the
+// auth interceptor has run.
+func logFinished(ctx context.Context, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK, codes.Canceled:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+
+	attrs := []any{"code", code.String(), "latency_ms", float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	FromContext(ctx).Log(ctx, level, "rpc finished", attrs...)
+}
+
+// requestAttrs picks the game and user ids out of a request message.
+func requestAttrs(req any) []any {
+	var attrs []any
+	if r, ok := req.(interface{ GetGameId() string }); ok && r.GetGameId() != "" {
+		attrs = append(attrs, "game_id", r.GetGameId())
+	}
+	if r, ok := req.(interface{ GetUserId() string }); ok && r.GetUserId() != "" {
+		attrs = append(attrs, "user_id", r.GetUserId())
+	}
+	if r, ok := req.(interface{ GetUserId1() string }); ok && r.GetUserId1() != "" {
+		attrs = append(attrs, "user_id1", r.GetUserId1())
+	}
+	if r, ok := req.(interface{ GetUserId2() string }); ok && r.GetUserId2() != "" {
+		attrs = append(attrs, "user_id2", r.GetUserId2())
+	}
+	return attrs
+}
+
+func outgoingRequestID(ctx context.Context) context.Context {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(RequestIDHeader)) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, RequestIDHeader, uuid.New().String())
+}
@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"", false},
+		{"0b6c1f5e-8a1d-4c55-9a53-5f3c2a0e8d11", true},
+		{"client:42.retry_1", true},
+		{strings.Repeat("a", maxRequestIDLength), true},
+		{strings.Repeat("a", maxRequestIDLength+1), false},
+		{"abc\nlevel=ERROR msg=forged", false},
+		{"abc def", false},
+		{`abc"}`, false},
+		{"zażółć", false},
+	}
+	for _, tt := range tests {
+		if got := ValidRequestID(tt.id); got != tt.want {
+			t.Errorf("ValidRequestID(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	var out bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&out, nil)))
+
+	// authenticate stands in for the auth interceptor, which runs after the
+	// logging one.
+	authenticate := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		AddAttrs(ctx, "caller_id", "alice")
+		return handler(ctx, req)
+	}
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/game.GameService/GetGame"}
+
+	for _, tt := range []struct {
+		requestID string
+		keep      bool
+	}{
+		{"client-1", true},
+		{"forged\nlevel=ERROR", false},
+	} {
+		out.Reset()
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, tt.requestID))
+		_, err := interceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+			return authenticate(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+				FromContext(ctx).Info("handled")
+				return nil, nil
+			})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("logged %d lines, want 2:\n%s", len(lines), out.String())
+		}
+		for _, line := range lines {
+			if !strings.Contains(line, "caller_id=alice") {
+				t.Errorf("line without the caller: %s", line)
+			}
+			if got := strings.Contains(line, "request_id="+tt.requestID); got != tt.keep {
+				t.Errorf("request id %q kept: %v, want %v: %s", tt.requestID, got, tt.keep, line)
+			}
+		}
+	}
+}
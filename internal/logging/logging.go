@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+)
+
+type Config struct {
+	// Level is one of debug, info, warn or error.
+	Level string `yaml:"level" toml:"level"`
+	// Format is text or json.
+	Format string `yaml:"format" toml:"format"`
+}
+
+type loggerKey struct{}
+
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return level, fmt.Errorf("invalid log level %q", name)
+	}
+	return level, nil
+}
+
+func (c Config) Validate() error {
+	if _, err := ParseLevel(c.Level); err != nil {
+		return err
+	}
+	switch c.Format {
+	case "", "text", "json":
+		return nil
+	default:
+		return fmt.Errorf("invalid log format %q", c.Format)
+	}
+}
+
+// Setup installs the default logger. Output of the standard log package is
+// routed through it as well and logged at info level.
+func Setup(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	level, _ := ParseLevel(cfg.Level)
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler = slog.NewTextHandler(os.Stderr, opts)
+	if cfg.Format == "json" {
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// requestLogger holds the logger of a request, so that interceptors running
+// after the logging ones can add to it, the line logged when the call
+// finishes included.
+type requestLogger struct {
+	logger atomic.Pointer[slog.Logger]
+}
+
+// FromContext returns the request scoped logger stored by the interceptors,
+// or the default logger outside of a request.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*requestLogger); ok {
+		return l.logger.Load()
+	}
+	return slog.Default()
+}
+
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	l := &requestLogger{}
+	l.logger.Store(logger)
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// AddAttrs adds attributes to the logger of the request in ctx, for every
+// line logged from then on. Outside of a request it does nothing.
+func AddAttrs(ctx context.Context, args ...any) {
+	if l, ok := ctx.Value(loggerKey{}).(*requestLogger); ok {
+		l.logger.Store(l.logger.Load().With(args...))
+	}
+}
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "battleships"
+
+var (
+	rpcCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_calls_total",
+		Help:      "Finished gRPC calls by method and status code.",
+	}, []string{"method", "code"})
+
+	rpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rpc_duration_seconds",
+		Help:      "Duration of unary gRPC calls.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	ActiveStreams = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "player_move_streams_active",
+		Help:      "Currently connected players, over PlayerMove streams or WebSockets.",
+	})
+
+	QueuedPlayers = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "matchmaking_queue_players",
+		Help:      "Players waiting to be challenged, with presence IN_QUEUE.",
+	})
+
+	GamesCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "games_created_total",
+		Help:      "Games created.",
+	})
+
+	GamesFinished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "games_finished_total",
+		Help:      "Games that ended, by how (FINISHED, RESIGNED).",
+	}, []string{"status"})
+
+	UsersCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "users_created_total",
+		Help:      "Users created.",
+	})
+
+	AccountsLocked = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "accounts_locked_total",
+		Help:      "Accounts locked after too many failed logins.",
+	})
+
+	AchievementsUnlocked = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "achievements_unlocked_total",
+		Help:      "Achievements unlocked by players, by achievement.",
+	}, []string{"achievement"})
+
+	EventErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "event_errors_total",
+		Help:      "Game events answered with an ERROR event, by error code.",
+	}, []string{"code"})
+
+	Notifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_total",
+		Help:      "Notifications about correspondence games, by kind and whether they were delivered (sent, failed).",
+	}, []string{"kind", "result"})
+
+	// Moves per second and the hit ratio are derived from this counter, e.g.
+	// rate(battleships_moves_total{result="HIT"}[5m]) / rate(battleships_moves_total{result=~"HIT|MISS"}[5m]).
+	Moves = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "moves_total",
+		Help:      "Processed moves by result (HIT, MISS, TAKEN).",
+	}, []string{"result"})
+
+	RateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "Calls and stream events rejected by the rate limiter, by method and scope (ip, user, stream).",
+	}, []string{"method", "scope"})
+
+	storeQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "store_query_duration_seconds",
+		Help:      "Duration of store operations.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"store", "operation"})
+)
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveQuery starts timing a store operation; call the returned function when it is done:
+//
+//	defer metrics.ObserveQuery("game", "move")()
+func ObserveQuery(store, operation string) func() {
+	start := time.Now()
+	return func() {
+		storeQueryDuration.WithLabelValues(store, operation).Observe(time.Since(start).Seconds())
+	}
+}
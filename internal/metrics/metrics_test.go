@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	const method = "/test.Service/Unary"
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{code: codes.OK},
+		{err: status.Error(codes.NotFound, "game not found"), code: codes.NotFound},
+		{err: io.EOF, code: codes.Unknown},
+	}
+	for _, tt := range tests {
+		before := testutil.ToFloat64(rpcCalls.WithLabelValues(method, tt.code.String()))
+		_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+			return nil, tt.err
+		})
+		if err != tt.err {
+			t.Errorf("interceptor returned %v, want %v", err, tt.err)
+		}
+		if got := testutil.ToFloat64(rpcCalls.WithLabelValues(method, tt.code.String())) - before; got != 1 {
+			t.Errorf("%s calls counted %v times, want once", tt.code, got)
+		}
+	}
+	if n := testutil.CollectAndCount(rpcDuration, namespace+"_rpc_duration_seconds"); n == 0 {
+		t.Error("no latency observed")
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	const method = "/test.Service/Stream"
+	interceptor := StreamServerInterceptor()
+	err := interceptor(nil, nil, &grpc.StreamServerInfo{FullMethod: method}, func(srv any, stream grpc.ServerStream) error {
+		return status.Error(codes.Unavailable, "server is shutting down")
+	})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("interceptor returned %v", err)
+	}
+	if got := testutil.ToFloat64(rpcCalls.WithLabelValues(method, codes.Unavailable.String())); got != 1 {
+		t.Errorf("stream counted %v times, want once", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	GamesCreated.Inc()
+	ObserveQuery("game", "test")()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{namespace + "_games_created_total", `store_query_duration_seconds_count{operation="test",store="game"} 1`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/metrics does not expose %s", want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const defaultMailFrom = "battleships@localhost"
+
+// MailDir writes every notification as an email into its own file in a
+// directory. Files are renamed into place once written, so a mail sink
+// watching the directory never reads half a message.
+type MailDir struct {
+	dir  string
+	from string
+}
+
+func NewMailDir(dir, from string) *MailDir {
+	if from == "" {
+		from = defaultMailFrom
+	}
+	return &MailDir{dir: dir, from: from}
+}
+
+func (m *MailDir) Notify(ctx context.Context, n Notification) error {
+	if n.Email == "" {
+		return nil
+	}
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	subject, body := message(n)
+	now := time.Now()
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", (&mail.Address{Name: n.Name, Address: n.Email}).String())
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(body)
+
+	name := fmt.Sprintf("%d-%s-%s.eml", now.UnixNano(), n.GameId, n.UserId)
+	tmp := filepath.Join(m.dir, "."+name)
+	if err := os.WriteFile(tmp, msg.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(m.dir, name))
+}
+
+func message(n Notification) (subject, body string) {
+	switch {
+	case n.Kind == YourTurn:
+		subject = fmt.Sprintf("Your turn against %s", n.Opponent)
+		body = fmt.Sprintf("%s has fired. It is your turn in game %s.\r\n", n.Opponent, n.GameId)
+		if !n.Deadline.IsZero() {
+			body += fmt.Sprintf("Move before %s or you lose the game.\r\n", n.Deadline.UTC().Format(time.RFC1123))
+		}
+	case n.Won:
+		subject = fmt.Sprintf("You won against %s", n.Opponent)
+		body = fmt.Sprintf("You won game %s against %s.\r\n", n.GameId, n.Opponent)
+	default:
+		subject = fmt.Sprintf("You lost against %s", n.Opponent)
+		body = fmt.Sprintf("You lost game %s against %s.\r\n", n.GameId, n.Opponent)
+	}
+	return subject, body
+}
@@ -0,0 +1,83 @@
+// Package notify tells players about their correspondence games while they
+// are away, through a webhook or as emails dropped into a directory.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type Kind string
+
+const (
+	// YourTurn is sent to a player when their opponent has moved.
+	YourTurn Kind = "YOUR_TURN"
+	// GameOver is sent to both players when the game ends.
+	GameOver Kind = "GAME_OVER"
+)
+
+// Notification is sent as JSON to webhooks.
+type Notification struct {
+	Kind   Kind   `json:"kind"`
+	GameId string `json:"game_id"`
+	// UserId, Name and Email are of the player being notified.
+	UserId   string `json:"user_id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Opponent string `json:"opponent"`
+	// Deadline is when the turn is forfeited, for YourTurn.
+	Deadline time.Time `json:"deadline,omitzero"`
+	// Won tells GameOver notifications apart.
+	Won bool `json:"won,omitempty"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+type Config struct {
+	// WebhookURL receives every notification as a JSON POST. Empty disables it.
+	WebhookURL string `yaml:"webhook_url" toml:"webhook_url"`
+	// MailDir receives every notification as an email in its own .eml file,
+	// for a local mail sink to pick up. Empty disables it.
+	MailDir string `yaml:"mail_dir" toml:"mail_dir"`
+	// MailFrom is the sender of the emails.
+	MailFrom string `yaml:"mail_from" toml:"mail_from"`
+}
+
+func (c Config) Validate() error {
+	if c.WebhookURL == "" {
+		return nil
+	}
+	u, err := url.Parse(c.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid notify.webhook_url %q", c.WebhookURL)
+	}
+	return nil
+}
+
+// New returns a notifier that sends to every sink configured. Without any,
+// notifications are dropped.
+func New(cfg Config) Notifier {
+	var sinks multi
+	if cfg.WebhookURL != "" {
+		sinks = append(sinks, NewWebhook(cfg.WebhookURL))
+	}
+	if cfg.MailDir != "" {
+		sinks = append(sinks, NewMailDir(cfg.MailDir, cfg.MailFrom))
+	}
+	return sinks
+}
+
+type multi []Notifier
+
+func (m multi) Notify(ctx context.Context, n Notification) error {
+	var errs []error
+	for _, sink := range m {
+		errs = append(errs, sink.Notify(ctx, n))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,166 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var won = Notification{
+	Kind:     GameOver,
+	GameId:   "g1",
+	UserId:   "alice",
+	Name:     "Alice Ż",
+	Email:    "alice@example.com",
+	Opponent: "Bob",
+	Won:      true,
+}
+
+func TestWebhook(t *testing.T) {
+	type request struct {
+		n           Notification
+		contentType string
+	}
+	requests := make(chan request, 2)
+	status := make(chan int, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n Notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			t.Errorf("webhook body: %v", err)
+		}
+		requests <- request{n, r.Header.Get("Content-Type")}
+		w.WriteHeader(<-status)
+	}))
+	defer server.Close()
+	webhook := NewWebhook(server.URL)
+
+	status <- http.StatusNoContent
+	if err := webhook.Notify(context.Background(), won); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-requests; got.n != won || got.contentType != "application/json" {
+		t.Errorf("webhook received %+v as %q, want %+v as JSON", got.n, got.contentType, won)
+	}
+
+	status <- http.StatusBadGateway
+	if err := webhook.Notify(context.Background(), won); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("webhook answering 502: error %v", err)
+	}
+}
+
+func TestMailDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	sink := NewMailDir(dir, "")
+
+	if err := sink.Notify(context.Background(), won); err != nil {
+		t.Fatal(err)
+	}
+	noEmail := won
+	noEmail.Email = ""
+	if err := sink.Notify(context.Background(), noEmail); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || !strings.HasSuffix(entries[0].Name(), "-g1-alice.eml") {
+		t.Fatalf("mail dir holds %v, want one email", entries)
+	}
+	f, err := os.Open(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	msg, err := mail.ReadMessage(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := msg.Header.AddressList("To")
+	if err != nil || len(to) != 1 || to[0].Name != won.Name || to[0].Address != won.Email {
+		t.Errorf("To = %v, %v", to, err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "You won against Bob" {
+		t.Errorf("Subject = %q, %v", subject, err)
+	}
+	if from := msg.Header.Get("From"); from != defaultMailFrom {
+		t.Errorf("From = %q, want %q", from, defaultMailFrom)
+	}
+	if body, _ := io.ReadAll(msg.Body); !strings.Contains(string(body), "You won game g1 against Bob.") {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	deadline := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		n             Notification
+		subject, body string
+	}{
+		{n: Notification{Kind: YourTurn, GameId: "g1", Opponent: "Bob"}, subject: "Your turn against Bob", body: "Bob has fired. It is your turn in game g1.\r\n"},
+		{n: Notification{Kind: YourTurn, GameId: "g1", Opponent: "Bob", Deadline: deadline}, subject: "Your turn against Bob", body: "Move before Sun, 01 Mar 2026 12:00:00 UTC or you lose the game."},
+		{n: Notification{Kind: GameOver, GameId: "g1", Opponent: "Bob", Won: true}, subject: "You won against Bob", body: "You won game g1 against Bob."},
+		{n: Notification{Kind: GameOver, GameId: "g1", Opponent: "Bob"}, subject: "You lost against Bob", body: "You lost game g1 against Bob."},
+	}
+	for _, tt := range tests {
+		subject, body := message(tt.n)
+		if subject != tt.subject || !strings.Contains(body, tt.body) {
+			t.Errorf("message(%+v) = %q, %q, want %q and a body with %q", tt.n, subject, body, tt.subject, tt.body)
+		}
+	}
+}
+
+// failing is a sink that always fails.
+type failing struct{ calls *int }
+
+func (f failing) Notify(ctx context.Context, n Notification) error {
+	*f.calls++
+	return io.ErrUnexpectedEOF
+}
+
+func TestMulti(t *testing.T) {
+	dir := t.TempDir()
+	calls := 0
+	sinks := multi{failing{&calls}, NewMailDir(dir, "")}
+
+	if err := sinks.Notify(context.Background(), won); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("error %v, want the error of the failing sink", err)
+	}
+	if entries, _ := os.ReadDir(dir); calls != 1 || len(entries) != 1 {
+		t.Errorf("a failing sink stopped the others: %d calls, %d emails", calls, len(entries))
+	}
+	if err := New(Config{}).Notify(context.Background(), won); err != nil {
+		t.Errorf("notifier without sinks: %v", err)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{url: "", valid: true},
+		{url: "https://example.com/hook", valid: true},
+		{url: "http://localhost:8080", valid: true},
+		{url: "ftp://example.com", valid: false},
+		{url: "example.com/hook", valid: false},
+		{url: "http://", valid: false},
+	}
+	for _, tt := range tests {
+		if err := (Config{WebhookURL: tt.url}).Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate(%q) = %v, want valid %t", tt.url, err, tt.valid)
+		}
+	}
+}
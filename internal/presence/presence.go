@@ -0,0 +1,240 @@
+// Package presence tracks whether users are online and what they are doing,
+// from the streams they have open, and tells watchers when that changes.
+package presence
+
+import (
+	"sync"
+
+	"github.com/gosukretess/battleships/internal/metrics"
+)
+
+// Status of a user, named like userpb.PresenceStatus. A user is in the most
+// engaged status any of their streams is in.
+type Status string
+
+const (
+	Offline Status = "OFFLINE"
+	// Idle users have a stream open but are not playing or waiting for a game.
+	Idle Status = "IDLE"
+	// InQueue users are waiting to be challenged.
+	InQueue Status = "IN_QUEUE"
+	// InGame users are connected to a game in progress.
+	InGame Status = "IN_GAME"
+)
+
+type Update struct {
+	UserId string
+	Status Status
+}
+
+type Tracker struct {
+	mu       sync.Mutex
+	users    map[string]*activity
+	watchers map[*Watcher]struct{}
+}
+
+// activity counts the streams of one user.
+type activity struct {
+	streams int
+	queued  int
+	games   map[string]int
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		users:    make(map[string]*activity),
+		watchers: make(map[*Watcher]struct{}),
+	}
+}
+
+// Status returns the current status of a user.
+func (t *Tracker) Status(userId string) Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.users[userId].status()
+}
+
+// Connect counts a stream opened by a user. Every call must be paired with
+// Disconnect.
+func (t *Tracker) Connect(userId string) {
+	t.update(userId, func(a *activity) { a.streams++ })
+}
+
+func (t *Tracker) Disconnect(userId string) {
+	t.update(userId, func(a *activity) { a.streams-- })
+}
+
+// EnterGame marks a stream of a user as connected to a game. Every call must
+// be paired with LeaveGame.
+func (t *Tracker) EnterGame(userId, gameId string) {
+	t.update(userId, func(a *activity) { a.games[gameId]++ })
+}
+
+func (t *Tracker) LeaveGame(userId, gameId string) {
+	t.update(userId, func(a *activity) {
+		if a.games[gameId]--; a.games[gameId] <= 0 {
+			delete(a.games, gameId)
+		}
+	})
+}
+
+// EnterQueue marks a stream of a user as waiting for a game. Every call must
+// be paired with LeaveQueue.
+func (t *Tracker) EnterQueue(userId string) {
+	t.update(userId, func(a *activity) { a.queued++ })
+}
+
+func (t *Tracker) LeaveQueue(userId string) {
+	t.update(userId, func(a *activity) { a.queued-- })
+}
+
+// update changes the activity of a user and notifies the watchers of the user
+// if that changed their status.
+func (t *Tracker) update(userId string, change func(*activity)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	a := t.users[userId]
+	if a == nil {
+		a = &activity{games: make(map[string]int)}
+		t.users[userId] = a
+	}
+	before, wasQueued := a.status(), a.queued > 0
+	change(a)
+	after, queued := a.status(), a.queued > 0
+	if queued != wasQueued {
+		if queued {
+			metrics.QueuedPlayers.Inc()
+		} else {
+			metrics.QueuedPlayers.Dec()
+		}
+	}
+	if after == Offline && len(a.games) == 0 && a.queued <= 0 {
+		delete(t.users, userId)
+	}
+	if after == before {
+		return
+	}
+	for w := range t.watchers {
+		if w.watched[userId] {
+			w.push(Update{UserId: userId, Status: after})
+		}
+	}
+}
+
+func (a *activity) status() Status {
+	switch {
+	case a == nil || a.streams <= 0:
+		return Offline
+	case len(a.games) > 0:
+		return InGame
+	case a.queued > 0:
+		return InQueue
+	default:
+		return Idle
+	}
+}
+
+// Watcher collects status changes of a set of users. Only the latest status
+// of each user is kept until it is read, so a slow reader never blocks the
+// tracker.
+type Watcher struct {
+	tracker *Tracker
+	// owner is the user the watcher belongs to.
+	owner   string
+	watched map[string]bool
+	pending map[string]Status
+	ready   chan struct{}
+}
+
+// Watch starts watching userIds on behalf of owner. Their current statuses
+// are the first updates. The watcher must be closed when it is no longer
+// needed.
+func (t *Tracker) Watch(owner string, userIds []string) *Watcher {
+	w := &Watcher{
+		tracker: t,
+		owner:   owner,
+		watched: make(map[string]bool),
+		pending: make(map[string]Status),
+		ready:   make(chan struct{}, 1),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.watchers[w] = struct{}{}
+	for _, userId := range userIds {
+		w.watched[userId] = true
+		w.push(Update{UserId: userId, Status: t.users[userId].status()})
+	}
+	return w
+}
+
+// Befriend makes the watchers owned by either user watch the other one, and
+// sends them their current status.
+func (t *Tracker) Befriend(userId1, userId2 string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for w := range t.watchers {
+		other := userId2
+		switch w.owner {
+		case userId1:
+		case userId2:
+			other = userId1
+		default:
+			continue
+		}
+		w.watched[other] = true
+		w.push(Update{UserId: other, Status: t.users[other].status()})
+	}
+}
+
+// Unfriend stops the watchers owned by either user from watching the other.
+func (t *Tracker) Unfriend(userId1, userId2 string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for w := range t.watchers {
+		switch w.owner {
+		case userId1:
+			delete(w.watched, userId2)
+			delete(w.pending, userId2)
+		case userId2:
+			delete(w.watched, userId1)
+			delete(w.pending, userId1)
+		}
+	}
+}
+
+// push is called with the tracker locked.
+func (w *Watcher) push(u Update) {
+	w.pending[u.UserId] = u.Status
+	select {
+	case w.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Ready is signalled when there are updates to read with Updates.
+func (w *Watcher) Ready() <-chan struct{} {
+	return w.ready
+}
+
+// Updates returns the changes since the last call.
+func (w *Watcher) Updates() []Update {
+	w.tracker.mu.Lock()
+	defer w.tracker.mu.Unlock()
+
+	updates := make([]Update, 0, len(w.pending))
+	for userId, status := range w.pending {
+		updates = append(updates, Update{UserId: userId, Status: status})
+	}
+	clear(w.pending)
+	return updates
+}
+
+func (w *Watcher) Close() {
+	w.tracker.mu.Lock()
+	defer w.tracker.mu.Unlock()
+	delete(w.tracker.watchers, w)
+}
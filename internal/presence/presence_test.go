@@ -0,0 +1,37 @@
+package presence
+
+import (
+	"testing"
+
+	"github.com/gosukretess/battleships/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestQueuedPlayersGauge(t *testing.T) {
+	tracker := NewTracker()
+	start := testutil.ToFloat64(metrics.QueuedPlayers)
+	queued := func(want float64) {
+		t.Helper()
+		if got := testutil.ToFloat64(metrics.QueuedPlayers) - start; got != want {
+			t.Errorf("%v players queued, want %v", got, want)
+		}
+	}
+
+	tracker.Connect("alice")
+	tracker.EnterQueue("alice")
+	// A second stream of the same player waiting does not count twice.
+	tracker.Connect("alice")
+	tracker.EnterQueue("alice")
+	tracker.Connect("bob")
+	tracker.EnterQueue("bob")
+	queued(2)
+	if status := tracker.Status("alice"); status != InQueue {
+		t.Errorf("alice is %s, want %s", status, InQueue)
+	}
+
+	tracker.LeaveQueue("alice")
+	queued(2)
+	tracker.LeaveQueue("alice")
+	tracker.LeaveQueue("bob")
+	queued(0)
+}
@@ -0,0 +1,107 @@
+package ratelimit_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gosukretess/battleships/internal/gateway"
+	"github.com/gosukretess/battleships/internal/ratelimit"
+	"github.com/gosukretess/battleships/proto/userpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type userServer struct {
+	userpb.UnimplementedUserServiceServer
+}
+
+func (userServer) Login(ctx context.Context, req *userpb.LoginRequest) (*userpb.LoginResponse, error) {
+	return &userpb.LoginResponse{}, nil
+}
+
+// startServer runs a gRPC server on the loopback interface that allows one
+// Login per client address.
+func startServer(t *testing.T) string {
+	t.Helper()
+
+	limiter := ratelimit.New(ratelimit.Config{
+		Methods: map[string]ratelimit.MethodLimits{"Login": {PerIP: ratelimit.Limit{Rate: 0.001, Burst: 1}}},
+	})
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(limiter.UnaryServerInterceptor()))
+	userpb.RegisterUserServiceServer(srv, userServer{})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func TestGatewaySpoofedClientIP(t *testing.T) {
+	gw, err := gateway.New(startServer(t), gateway.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gw.Close()
+
+	login := func(remoteAddr string, header http.Header) int {
+		req := httptest.NewRequest(http.MethodPost, "/v1/login", strings.NewReader(`{"email": "alice@example.com"}`))
+		req.RemoteAddr = remoteAddr
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		rec := httptest.NewRecorder()
+		gw.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := login("192.0.2.1:1234", nil); code != http.StatusOK {
+		t.Fatalf("first login: status %d, want %d", code, http.StatusOK)
+	}
+	spoofed := []http.Header{
+		{"X-Forwarded-For": {"198.51.100.7"}},
+		{"X-Forwarded-For": {"198.51.100.8, 198.51.100.9"}},
+		{"Grpc-Metadata-X-Battleships-Client-Ip": {"198.51.100.10"}},
+		{"Grpc-Metadata-X-Battleships-Client-Ip": {"198.51.100.11"}, "Grpc-Metadata-X-Battleships-Gateway-Token": {"guess"}},
+	}
+	for _, header := range spoofed {
+		if code := login("192.0.2.1:1234", header); code != http.StatusTooManyRequests {
+			t.Errorf("login with %v: status %d, want %d", header, code, http.StatusTooManyRequests)
+		}
+	}
+
+	if code := login("192.0.2.2:1234", nil); code != http.StatusOK {
+		t.Errorf("login from another client: status %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestLoopbackForwardedForIgnored(t *testing.T) {
+	conn, err := grpc.NewClient(startServer(t), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := userpb.NewUserServiceClient(conn)
+
+	for i, ip := range []string{"198.51.100.1", "198.51.100.2"} {
+		ctx := metadata.AppendToOutgoingContext(context.Background(),
+			"x-forwarded-for", ip, "x-battleships-client-ip", ip, "x-battleships-gateway-token", "guess")
+		_, err := client.Login(ctx, &userpb.LoginRequest{})
+		want := codes.OK
+		if i > 0 {
+			want = codes.ResourceExhausted
+		}
+		if status.Code(err) != want {
+			t.Errorf("login %d claiming to be %s: error %v, want %v", i+1, ip, err, want)
+		}
+	}
+}
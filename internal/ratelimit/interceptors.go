@@ -0,0 +1,191 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/gosukretess/battleships/internal/auth"
+	"github.com/gosukretess/battleships/internal/gateway"
+	"github.com/gosukretess/battleships/internal/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Health probes must never be throttled, or an orchestrator polling them
+// would take the server out of rotation.
+const healthServicePrefix = "/grpc.health.v1.Health/"
+
+// UnaryServerInterceptor rejects calls over the per-IP limit of their method
+// with ResourceExhausted. Chain it ahead of auth, so that a flood is turned
+// away before any session is looked up.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := l.checkIP(info.FullMethod, clientIP(ctx)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// UserUnaryServerInterceptor rejects calls over the per-user limit of their
+// method with ResourceExhausted. Chain it after auth, which tells who the
+// caller is.
+func (l *Limiter) UserUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := l.checkUser(info.FullMethod, userID(ctx, req)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor applies the per-IP limit when a stream is opened.
+// Like UnaryServerInterceptor it goes ahead of auth.
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.checkIP(info.FullMethod, clientIP(stream.Context())); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// UserStreamServerInterceptor applies the per-user limit when a stream is
+// opened and sets up the stream event limit for AllowEvent. Chain it after
+// auth.
+func (l *Limiter) UserStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		userId, _ := auth.UserID(stream.Context())
+		if err := l.checkUser(info.FullMethod, userId); err != nil {
+			return err
+		}
+		if !info.IsClientStream {
+			return handler(srv, stream)
+		}
+		ctx := l.WithEventLimit(stream.Context(), info.FullMethod, userId)
+		return handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// Check applies the per-IP and per-user limits of method to a connection
+// that does not go through the gRPC server, such as a WebSocket.
+func (l *Limiter) Check(method, ip, userId string) error {
+	if err := l.checkIP(method, ip); err != nil {
+		return err
+	}
+	return l.checkUser(method, userId)
+}
+
+func (l *Limiter) checkIP(method, ip string) error {
+	if strings.HasPrefix(method, healthServicePrefix) {
+		return nil
+	}
+	limit := l.cfg.limits(method).PerIP
+	if ip != "" && limit.enabled() {
+		if !l.allow(ScopeIP+"|"+method+"|"+ip, limit) {
+			return rejected(method, ScopeIP)
+		}
+	}
+	return nil
+}
+
+func (l *Limiter) checkUser(method, user string) error {
+	if strings.HasPrefix(method, healthServicePrefix) {
+		return nil
+	}
+	limit := l.cfg.limits(method).PerUser
+	if user != "" && limit.enabled() {
+		if !l.allow(ScopeUser+"|"+method+"|"+user, limit) {
+			return rejected(method, ScopeUser)
+		}
+	}
+	return nil
+}
+
+type eventLimitKey struct{}
+
+// eventLimit takes a token for every event a client sends on a stream.
+type eventLimit struct {
+	method string
+	allow  func() bool
+}
+
+// WithEventLimit returns a context in which AllowEvent applies the stream
+// event limit to the events of a stream of method. The streams of one user
+// share a bucket, so that opening more of them does not raise the limit.
+func (l *Limiter) WithEventLimit(ctx context.Context, method, userId string) context.Context {
+	limit := l.cfg.StreamEvents
+	if !limit.enabled() {
+		return ctx
+	}
+	allow := newStreamLimiter(limit).Allow
+	if userId != "" {
+		key := ScopeStream + "|" + method + "|" + userId
+		allow = func() bool { return l.allow(key, limit) }
+	}
+	return context.WithValue(ctx, eventLimitKey{}, &eventLimit{method: method, allow: allow})
+}
+
+// AllowEvent takes a token for one event received on the stream of ctx. Over
+// the limit it returns a ResourceExhausted error; the event should be
+// rejected, but the stream can stay open.
+func AllowEvent(ctx context.Context) error {
+	limit, ok := ctx.Value(eventLimitKey{}).(*eventLimit)
+	if !ok || limit.allow() {
+		return nil
+	}
+	return rejected(limit.method, ScopeStream)
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func rejected(method, scope string) error {
+	metrics.RateLimited.WithLabelValues(method, scope).Inc()
+	return status.Errorf(codes.ResourceExhausted, "too many requests, %s rate limit exceeded", scope)
+}
+
+// userID returns the authenticated caller, or for calls without a session,
+// the user named in the request message.
+func userID(ctx context.Context, req any) string {
+	if userId, ok := auth.UserID(ctx); ok {
+		return userId
+	}
+	if r, ok := req.(interface{ GetUserId() string }); ok && r.GetUserId() != "" {
+		return r.GetUserId()
+	}
+	if r, ok := req.(interface{ GetUserId1() string }); ok {
+		return r.GetUserId1()
+	}
+	return ""
+}
+
+// clientIP returns the address of the caller. Calls forwarded by the REST
+// gateway carry the address of the HTTP client, set by the gateway itself.
+func clientIP(ctx context.Context) string {
+	if ip, ok := gateway.ClientIP(ctx); ok {
+		return ip
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	return host
+}
+
+func methodName(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
@@ -0,0 +1,161 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/gosukretess/battleships/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const loginMethod = "/user.UserService/Login"
+
+func withPeer(ctx context.Context, addr string) context.Context {
+	tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+	if err != nil {
+		panic(err)
+	}
+	return peer.NewContext(ctx, &peer.Peer{Addr: tcpAddr})
+}
+
+// chain runs the interceptors in the order the server chains them, with
+// authenticate standing in for the auth interceptor.
+func chain(l *Limiter, authenticate grpc.UnaryServerInterceptor) func(ctx context.Context) error {
+	info := &grpc.UnaryServerInfo{FullMethod: loginMethod}
+	interceptors := []grpc.UnaryServerInterceptor{l.UnaryServerInterceptor(), authenticate, l.UserUnaryServerInterceptor()}
+	return func(ctx context.Context) error {
+		var call grpc.UnaryHandler = func(ctx context.Context, req any) (any, error) { return nil, nil }
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], call
+			call = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		_, err := call(ctx, nil)
+		return err
+	}
+}
+
+func TestIPLimitRunsBeforeAuth(t *testing.T) {
+	l := New(Config{Default: MethodLimits{PerIP: Limit{Rate: 0.001, Burst: 2}}})
+	lookups := 0
+	call := chain(l, func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		lookups++
+		return handler(ctx, req)
+	})
+
+	ctx := withPeer(context.Background(), "192.0.2.1:1234")
+	rejected := 0
+	for range 10 {
+		if err := call(ctx); status.Code(err) == codes.ResourceExhausted {
+			rejected++
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if rejected != 8 {
+		t.Errorf("%d calls rejected, want 8", rejected)
+	}
+	if lookups != 2 {
+		t.Errorf("auth ran for %d calls, want only the 2 allowed", lookups)
+	}
+}
+
+func TestUserLimitUsesAuthenticatedCaller(t *testing.T) {
+	l := New(Config{Default: MethodLimits{PerUser: Limit{Rate: 0.001, Burst: 1}}})
+	call := chain(l, func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(auth.WithUserID(ctx, "alice"), req)
+	})
+
+	// The second call comes from another address but the same user.
+	if err := call(withPeer(context.Background(), "192.0.2.1:1234")); err != nil {
+		t.Fatal(err)
+	}
+	if err := call(withPeer(context.Background(), "192.0.2.2:1234")); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("second call of the same user: error %v, want ResourceExhausted", err)
+	}
+}
+
+func TestEventLimitSharedByUser(t *testing.T) {
+	l := New(Config{StreamEvents: Limit{Rate: 0.001, Burst: 3}})
+	const method = "/game.GameService/PlayerMove"
+
+	tests := []struct {
+		name    string
+		streams []context.Context
+		allowed int
+	}{
+		{
+			name:    "one stream",
+			streams: []context.Context{l.WithEventLimit(context.Background(), method, "alice")},
+			allowed: 3,
+		},
+		{
+			name: "two streams of one user",
+			streams: []context.Context{
+				l.WithEventLimit(context.Background(), method, "bob"),
+				l.WithEventLimit(context.Background(), method, "bob"),
+			},
+			allowed: 3,
+		},
+		{
+			name: "anonymous streams",
+			streams: []context.Context{
+				l.WithEventLimit(context.Background(), method, ""),
+				l.WithEventLimit(context.Background(), method, ""),
+			},
+			allowed: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed := 0
+			for range 5 {
+				for _, ctx := range tt.streams {
+					if err := AllowEvent(ctx); err == nil {
+						allowed++
+					} else if status.Code(err) != codes.ResourceExhausted {
+						t.Fatal(err)
+					}
+				}
+			}
+			if allowed != tt.allowed {
+				t.Errorf("%d events allowed, want %d", allowed, tt.allowed)
+			}
+		})
+	}
+
+	if err := AllowEvent(context.Background()); err != nil {
+		t.Errorf("event without a limit: %v", err)
+	}
+}
+
+func TestUserStreamLimit(t *testing.T) {
+	l := New(Config{Default: MethodLimits{PerUser: Limit{Rate: 0.001, Burst: 1}}})
+	interceptor := l.UserStreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/game.GameService/PlayerMove", IsClientStream: true}
+	open := func(addr string) error {
+		ctx := auth.WithUserID(withPeer(context.Background(), addr), "alice")
+		return interceptor(nil, &fakeStream{ctx: ctx}, info, func(srv any, stream grpc.ServerStream) error { return nil })
+	}
+
+	if err := open("192.0.2.1:1234"); err != nil {
+		t.Fatal(err)
+	}
+	if err := open("192.0.2.2:1234"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("second stream of the same user from another address: error %v, want ResourceExhausted", err)
+	}
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	ScopeIP     = "ip"
+	ScopeUser   = "user"
+	ScopeStream = "stream"
+
+	// Buckets idle for this long are full again and can be forgotten.
+	idleTimeout   = 10 * time.Minute
+	sweepInterval = time.Minute
+)
+
+// Limit describes a token bucket. A zero Rate disables the limit.
+type Limit struct {
+	// Rate is the number of calls allowed per second on average.
+	Rate float64 `yaml:"rate" toml:"rate"`
+	// Burst is the number of calls allowed at once. Defaults to Rate, at least 1.
+	Burst int `yaml:"burst" toml:"burst"`
+}
+
+func (l Limit) enabled() bool {
+	return l.Rate > 0
+}
+
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return max(1, int(l.Rate))
+}
+
+func (l Limit) validate(name string) error {
+	if l.Rate < 0 || l.Burst < 0 {
+		return fmt.Errorf("rate limit %s must not be negative", name)
+	}
+	return nil
+}
+
+type MethodLimits struct {
+	PerIP   Limit `yaml:"per_ip" toml:"per_ip"`
+	PerUser Limit `yaml:"per_user" toml:"per_user"`
+}
+
+type Config struct {
+	// Default applies to every RPC without an entry in Methods.
+	Default MethodLimits `yaml:"default" toml:"default"`
+	// Methods overrides Default per RPC, keyed by method name ("CreateUser")
+	// or full method ("/user.UserService/CreateUser").
+	Methods map[string]MethodLimits `yaml:"methods" toml:"methods"`
+	// StreamEvents limits the events a user may send on their PlayerMove
+	// streams and WebSockets together.
+	StreamEvents Limit `yaml:"stream_events" toml:"stream_events"`
+}
+
+func (c Config) Validate() error {
+	if err := c.Default.PerIP.validate("per_ip"); err != nil {
+		return err
+	}
+	if err := c.Default.PerUser.validate("per_user"); err != nil {
+		return err
+	}
+	for method, limits := range c.Methods {
+		if err := limits.PerIP.validate(method + ".per_ip"); err != nil {
+			return err
+		}
+		if err := limits.PerUser.validate(method + ".per_user"); err != nil {
+			return err
+		}
+	}
+	return c.StreamEvents.validate("stream_events")
+}
+
+// limits returns the limits configured for a full gRPC method name.
+func (c Config) limits(fullMethod string) MethodLimits {
+	if limits, ok := c.Methods[fullMethod]; ok {
+		return limits
+	}
+	if limits, ok := c.Methods[methodName(fullMethod)]; ok {
+		return limits
+	}
+	return c.Default
+}
+
+// Limiter keeps a token bucket per client key and method.
+type Limiter struct {
+	cfg       Config
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func New(cfg Config) *Limiter {
+	return &Limiter{
+		cfg:       cfg,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// allow takes a token from the bucket identified by key, creating it if needed.
+func (l *Limiter) allow(key string, limit Limit) bool {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > sweepInterval {
+		for k, b := range l.buckets {
+			if now.Sub(b.lastSeen) > idleTimeout {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limit.Rate), limit.burst())}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+	return b.limiter.AllowN(now, 1)
+}
+
+func newStreamLimiter(limit Limit) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(limit.Rate), limit.burst())
+}
@@ -0,0 +1,159 @@
+package tlsutil
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestServerConfigValidate(t *testing.T) {
+	tests := []struct {
+		cfg   ServerConfig
+		valid bool
+	}{
+		{cfg: ServerConfig{}, valid: true},
+		{cfg: ServerConfig{CertFile: "server.crt", KeyFile: "server.key"}, valid: true},
+		{cfg: ServerConfig{CertFile: "server.crt", KeyFile: "server.key", ClientCAFile: "ca.crt", ClientAuth: ClientAuthOptional}, valid: true},
+		{cfg: ServerConfig{CertFile: "server.crt"}},
+		{cfg: ServerConfig{KeyFile: "server.key"}},
+		{cfg: ServerConfig{ClientCAFile: "ca.crt"}},
+		{cfg: ServerConfig{CertFile: "server.crt", KeyFile: "server.key", ClientAuth: "sometimes"}},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate(%+v) = %v, want valid %t", tt.cfg, err, tt.valid)
+		}
+	}
+}
+
+// handshake runs a TLS handshake between a client and a server over a
+// loopback connection and returns the error of the client.
+func handshake(t *testing.T, server, client *tls.Config) error {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	serverErr := make(chan error, 1)
+	go func() {
+		raw, err := lis.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		conn := tls.Server(raw, server)
+		serverErr <- conn.Handshake()
+		conn.Close()
+	}()
+
+	raw, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer raw.Close()
+	raw.SetDeadline(time.Now().Add(5 * time.Second))
+	conn := tls.Client(raw, client)
+	err = conn.Handshake()
+	if err == nil {
+		// TLS 1.3 clients learn that their certificate was rejected on
+		// their first read. A server that accepted it just closes.
+		_, err = conn.Read(make([]byte, 1))
+		if err == io.EOF {
+			err = nil
+		}
+	}
+	if err == nil {
+		err = <-serverErr
+	}
+	return err
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	if err := GenerateDevCerts(dir, []string{"localhost", "127.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	file := func(name string) string { return filepath.Join(dir, name) }
+	withCert := ClientConfig{CAFile: file("ca.crt"), CertFile: file("client.crt"), KeyFile: file("client.key"), ServerName: "localhost"}
+	withoutCert := ClientConfig{CAFile: file("ca.crt"), ServerName: "localhost"}
+
+	tests := []struct {
+		name   string
+		server ServerConfig
+		client ClientConfig
+		ok     bool
+	}{
+		{name: "client certificate", server: ServerConfig{CertFile: file("server.crt"), KeyFile: file("server.key"), ClientCAFile: file("ca.crt")}, client: withCert, ok: true},
+		{name: "no client certificate", server: ServerConfig{CertFile: file("server.crt"), KeyFile: file("server.key"), ClientCAFile: file("ca.crt")}, client: withoutCert},
+		{name: "optional client certificate", server: ServerConfig{CertFile: file("server.crt"), KeyFile: file("server.key"), ClientCAFile: file("ca.crt"), ClientAuth: ClientAuthOptional}, client: withoutCert, ok: true},
+		{name: "server only", server: ServerConfig{CertFile: file("server.crt"), KeyFile: file("server.key")}, client: withoutCert, ok: true},
+		{name: "unknown server name", server: ServerConfig{CertFile: file("server.crt"), KeyFile: file("server.key")}, client: ClientConfig{CAFile: file("ca.crt"), ServerName: "example.com"}},
+		{name: "untrusted server", server: ServerConfig{CertFile: file("server.crt"), KeyFile: file("server.key")}, client: ClientConfig{Enabled: true, ServerName: "localhost"}},
+	}
+	for _, tt := range tests {
+		server, err := tt.server.TLSConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, err := tt.client.TLSConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := handshake(t, server, client); (err == nil) != tt.ok {
+			t.Errorf("%s: handshake error %v, want success %t", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir, next := t.TempDir(), t.TempDir()
+	for _, d := range []string{dir, next} {
+		if err := GenerateDevCerts(d, []string{"localhost"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	reloader, err := NewCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := reloader.GetCertificate(nil)
+
+	// Replace the pair, with a later modification time than the first.
+	later := time.Now().Add(time.Minute)
+	for _, name := range []string{"server.crt", "server.key"} {
+		data, err := os.ReadFile(filepath.Join(next, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filepath.Join(dir, name), later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+	second, _ := reloader.GetCertificate(nil)
+	if bytes.Equal(first.Certificate[0], second.Certificate[0]) {
+		t.Error("certificate not reloaded after the files changed")
+	}
+
+	// A broken file keeps the certificate in use.
+	if err := os.WriteFile(certFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later = later.Add(time.Minute)
+	if err := os.Chtimes(certFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if third, _ := reloader.GetClientCertificate(nil); third != second {
+		t.Error("broken certificate replaced the one in use")
+	}
+}
@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	for exporter, valid := range map[string]bool{"": true, ExporterNone: true, ExporterStdout: true, ExporterOTLP: true, "jaeger": false} {
+		if err := (Config{Exporter: exporter}).Validate(); (err == nil) != valid {
+			t.Errorf("Validate(%q) = %v, want valid %t", exporter, err, valid)
+		}
+	}
+}
+
+// span is the part of a span written by the stdout exporter the test reads.
+type span struct {
+	Name        string
+	SpanContext struct{ TraceID, SpanID string }
+	Parent      struct{ SpanID string }
+	Resource    []struct {
+		Key   string
+		Value struct{ Value any }
+	}
+}
+
+func TestSetupStdout(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterStdout, File: file}, "battleships-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, parent := Start(context.Background(), "PlayerMove/MOVE")
+	_, child := Start(ctx, "game.Move")
+	child.End()
+	parent.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	spans := map[string]span{}
+	for decoder := json.NewDecoder(f); ; {
+		var s span
+		if err := decoder.Decode(&s); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		spans[s.Name] = s
+	}
+
+	p, c := spans["PlayerMove/MOVE"], spans["game.Move"]
+	if p.Name == "" || c.Name == "" {
+		t.Fatalf("spans written: %v", spans)
+	}
+	if c.SpanContext.TraceID != p.SpanContext.TraceID || c.Parent.SpanID != p.SpanContext.SpanID {
+		t.Errorf("child span %+v is not in the trace of %+v", c.SpanContext, p.SpanContext)
+	}
+	service := ""
+	for _, attr := range p.Resource {
+		if attr.Key == "service.name" {
+			service, _ = attr.Value.Value.(string)
+		}
+	}
+	if service != "battleships-test" {
+		t.Errorf("service name %q, want battleships-test", service)
+	}
+}
+
+func TestSetupInvalid(t *testing.T) {
+	if _, err := Setup(context.Background(), Config{Exporter: "jaeger"}, "battleships-test"); err == nil || !strings.Contains(err.Error(), "jaeger") {
+		t.Errorf("unknown exporter: error %v", err)
+	}
+	shutdown, err := Setup(context.Background(), Config{}, "battleships-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown without an exporter: %v", err)
+	}
+}
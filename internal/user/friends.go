@@ -0,0 +1,154 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/gosukretess/battleships/internal/database"
+)
+
+// Friendship states, named like userpb.FriendStatus.
+const (
+	FriendAccepted = "FRIEND"
+	// FriendIncoming is a request the user has received.
+	FriendIncoming = "INCOMING"
+	// FriendOutgoing is a request the user has sent.
+	FriendOutgoing = "OUTGOING"
+)
+
+var (
+	ErrFriendExists          = errors.New("already friends or already requested")
+	ErrFriendRequestNotFound = errors.New("friend request not found")
+	ErrFriendNotFound        = errors.New("not friends and no request between the users")
+)
+
+type FriendDto struct {
+	User   UserDto
+	Status string
+	// Since is when the request was sent, or accepted once it was.
+	Since time.Time
+}
+
+// RequestFriend sends a friend request from userId to friendId. A request
+// the other way round is accepted instead, so the result is FriendAccepted
+// or FriendOutgoing. It returns ErrFriendExists if the users are friends or
+// userId has already asked.
+func (s *Store) RequestFriend(ctx context.Context, userId, friendId string) (string, error) {
+	ctx, done := s.instrument(ctx, "request_friend")
+	defer done()
+
+	// A pair of users has at most one row. When both ask at the same time,
+	// the insert of one of them fails and a second attempt accepts the
+	// request of the other.
+	state, err := s.requestFriend(ctx, userId, friendId)
+	if database.IsUniqueViolation(err) {
+		state, err = s.requestFriend(ctx, userId, friendId)
+	}
+	if database.IsUniqueViolation(err) {
+		return "", ErrFriendExists
+	}
+	return state, err
+}
+
+func (s *Store) requestFriend(ctx context.Context, userId, friendId string) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	var accepted sql.NullString
+	err = tx.QueryRowContext(ctx, "SELECT accepted FROM friends WHERE userid = ? AND friendid = ?", friendId, userId).Scan(&accepted)
+	switch {
+	case err == nil && accepted.Valid:
+		return "", ErrFriendExists
+	case err == nil:
+		if _, err := tx.ExecContext(ctx, "UPDATE friends SET accepted = ? WHERE userid = ? AND friendid = ?", now, friendId, userId); err != nil {
+			return "", err
+		}
+		return FriendAccepted, tx.Commit()
+	case err != sql.ErrNoRows:
+		return "", err
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO friends (userid, friendid, created) VALUES (?, ?, ?)", userId, friendId, now); err != nil {
+		return "", err
+	}
+	return FriendOutgoing, tx.Commit()
+}
+
+// AcceptFriend accepts the request requesterId sent to userId. It returns
+// ErrFriendRequestNotFound if there is no such request waiting.
+func (s *Store) AcceptFriend(ctx context.Context, userId, requesterId string) error {
+	ctx, done := s.instrument(ctx, "accept_friend")
+	defer done()
+
+	result, err := s.db.ExecContext(ctx, "UPDATE friends SET accepted = ? WHERE userid = ? AND friendid = ? AND accepted IS NULL",
+		time.Now().UTC().Format(time.RFC3339), requesterId, userId)
+	if err != nil {
+		return err
+	}
+	if err := requireRow(result); err != nil {
+		return ErrFriendRequestNotFound
+	}
+	return nil
+}
+
+// RemoveFriend ends a friendship, or declines or withdraws a request, between
+// two users. It returns ErrFriendNotFound if there is none.
+func (s *Store) RemoveFriend(ctx context.Context, userId, friendId string) error {
+	ctx, done := s.instrument(ctx, "remove_friend")
+	defer done()
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM friends WHERE (userid = ? AND friendid = ?) OR (userid = ? AND friendid = ?)",
+		userId, friendId, friendId, userId)
+	if err != nil {
+		return err
+	}
+	if err := requireRow(result); err != nil {
+		return ErrFriendNotFound
+	}
+	return nil
+}
+
+// GetFriends lists the friends of a user and the requests they have sent or
+// received, by name.
+func (s *Store) GetFriends(ctx context.Context, userId string) ([]FriendDto, error) {
+	ctx, done := s.instrument(ctx, "get_friends")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT u.id, u.name, COALESCE(u.email, ''), f.userid, f.created, f.accepted FROM friends f
+		JOIN users u ON u.id = CASE WHEN f.userid = ? THEN f.friendid ELSE f.userid END
+		WHERE f.userid = ? OR f.friendid = ?
+		ORDER BY u.name, u.id`, userId, userId, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var friends []FriendDto
+	for rows.Next() {
+		var f FriendDto
+		var requester, created string
+		var accepted sql.NullString
+		if err := rows.Scan(&f.User.Id, &f.User.Name, &f.User.Email, &requester, &created, &accepted); err != nil {
+			return nil, err
+		}
+		switch {
+		case accepted.Valid:
+			f.Status = FriendAccepted
+			created = accepted.String
+		case requester == userId:
+			f.Status = FriendOutgoing
+		default:
+			f.Status = FriendIncoming
+		}
+		f.Since, _ = time.Parse(time.RFC3339, created)
+		friends = append(friends, f)
+	}
+	return friends, rows.Err()
+}
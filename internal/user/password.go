@@ -0,0 +1,93 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	minPasswordLength = 8
+	// bcrypt ignores everything after the first 72 bytes.
+	maxPasswordLength = 72
+)
+
+type Config struct {
+	// SessionTTL is how long a session token stays valid after Login.
+	SessionTTL time.Duration `yaml:"session_ttl" toml:"session_ttl"`
+	// MaxFailedLogins locks the account after this many wrong passwords in a row.
+	MaxFailedLogins int `yaml:"max_failed_logins" toml:"max_failed_logins"`
+	// LockoutDuration is how long a locked account rejects logins.
+	LockoutDuration time.Duration `yaml:"lockout_duration" toml:"lockout_duration"`
+}
+
+func (c Config) Validate() error {
+	if c.SessionTTL <= 0 {
+		return errors.New("auth.session_ttl must be positive")
+	}
+	if c.MaxFailedLogins < 0 {
+		return errors.New("auth.max_failed_logins must not be negative")
+	}
+	if c.MaxFailedLogins > 0 && c.LockoutDuration <= 0 {
+		return errors.New("auth.lockout_duration must be positive when lockout is enabled")
+	}
+	return nil
+}
+
+// dummyHash is compared against when the email is unknown, so that a login
+// takes as long whether or not the account exists.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("battleships"), bcrypt.DefaultCost)
+
+// ValidatePassword checks the length limits of a new password.
+func ValidatePassword(password string) error {
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	if len(password) > maxPasswordLength {
+		return fmt.Errorf("password must be at most %d bytes", maxPasswordLength)
+	}
+	return nil
+}
+
+func validatePassword(password string) error {
+	if err := ValidatePassword(password); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
+func checkPassword(hash, password string) bool {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// newToken returns a random session token and the hash it is stored under.
+func newToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,70 +2,494 @@ package user
 
 import (
 	"context"
+	"errors"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/gosukretess/battleships/internal/achievement"
+	"github.com/gosukretess/battleships/internal/auth"
+	"github.com/gosukretess/battleships/internal/metrics"
+	"github.com/gosukretess/battleships/internal/presence"
+	"github.com/gosukretess/battleships/proto/gamepb"
 	"github.com/gosukretess/battleships/proto/userpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const maxNameLength = 64
+
+// errInvalidCredentials does not say whether the email or the password was
+// wrong.
+var errInvalidCredentials = status.Error(codes.Unauthenticated, "invalid email or password")
+
+// GameResigner ends the games of a user who deletes their account. The game
+// server implements it.
+type GameResigner interface {
+	ResignGames(ctx context.Context, userId string) (int, error)
+}
+
 type Server struct {
 	userpb.UnimplementedUserServiceServer
-	store *Store
+	store    *Store
+	presence *presence.Tracker
+	games    GameResigner
+	config   Config
+	stopOnce sync.Once
+	shutdown chan struct{}
 }
 
-func NewServer(store *Store) *Server {
-	return &Server{store: store}
+func NewServer(store *Store, tracker *presence.Tracker, games GameResigner, config Config) *Server {
+	return &Server{store: store, presence: tracker, games: games, config: config, shutdown: make(chan struct{})}
 }
 
 func (s *Server) GetUser(ctx context.Context, req *userpb.GetUserRequest) (*userpb.GetUserResponse, error) {
+	u, err := s.store.GetUser(ctx, req.GetId())
+	if err != nil {
+		return nil, storeError(err)
+	}
 
-	name, email, err := s.store.GetUser(req.GetId())
+	return &userpb.GetUserResponse{User: visibleUser(ctx, u)}, nil
+}
 
+func (s *Server) CreateUser(ctx context.Context, req *userpb.CreateUserRequest) (*userpb.CreateUserResponse, error) {
+	name, err := validateName(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	email, err := validateEmail(req.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePassword(req.GetPassword()); err != nil {
+		return nil, err
+	}
+	passwordHash, err := HashPassword(req.GetPassword())
 	if err != nil {
 		return nil, err
 	}
 
-	return &userpb.GetUserResponse{
-		User: &userpb.User{
-			Id:    req.GetId(),
-			Name:  name,
-			Email: email,
-		},
+	u := UserDto{Id: uuid.New().String(), Name: name, Email: email}
+	if err := s.store.CreateUser(ctx, u.Id, u.Name, u.Email, passwordHash); err != nil {
+		return nil, storeError(err)
+	}
+	metrics.UsersCreated.Inc()
+
+	return &userpb.CreateUserResponse{User: userToProto(u)}, nil
+}
+
+func (s *Server) GetUsers(ctx context.Context, req *userpb.GetUsersRequest) (*userpb.GetUsersResponse, error) {
+	users, err := s.store.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbUsers []*userpb.User
+	for _, u := range users {
+		pbUsers = append(pbUsers, visibleUser(ctx, u))
+	}
+
+	return &userpb.GetUsersResponse{
+		Users: pbUsers,
 	}, nil
 }
 
-func (s *Server) CreateUser(ctx context.Context, req *userpb.CreateUserRequest) (*userpb.CreateUserResponse, error) {
-	id := uuid.New().String()
-	err := s.store.CreateUser(id, req.GetName(), req.GetEmail())
+func (s *Server) UpdateUser(ctx context.Context, req *userpb.UpdateUserRequest) (*userpb.UpdateUserResponse, error) {
+	if err := requireCaller(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	if req.GetName() == "" && req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "nothing to update, set name or email")
+	}
+
+	u, err := s.store.GetUser(ctx, req.GetId())
 	if err != nil {
+		return nil, storeError(err)
+	}
+	if req.GetName() != "" {
+		if u.Name, err = validateName(req.GetName()); err != nil {
+			return nil, err
+		}
+	}
+	if req.GetEmail() != "" {
+		if u.Email, err = validateEmail(req.GetEmail()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.store.UpdateUser(ctx, u); err != nil {
+		return nil, storeError(err)
+	}
+	return &userpb.UpdateUserResponse{User: userToProto(u)}, nil
+}
+
+func (s *Server) DeleteUser(ctx context.Context, req *userpb.DeleteUserRequest) (*userpb.DeleteUserResponse, error) {
+	if err := requireCaller(ctx, req.GetId()); err != nil {
 		return nil, err
 	}
+	// The games are ended first, so that opponents watching them are told
+	// before the player is gone.
+	resignedGames, err := s.games.ResignGames(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.DeleteUser(ctx, req.GetId()); err != nil {
+		return nil, storeError(err)
+	}
+	return &userpb.DeleteUserResponse{ResignedGames: int32(resignedGames)}, nil
+}
+
+func (s *Server) FindUserByEmail(ctx context.Context, req *userpb.FindUserByEmailRequest) (*userpb.FindUserByEmailResponse, error) {
+	u, err := s.store.FindUserByEmail(ctx, normalizeEmail(req.GetEmail()))
+	if err != nil {
+		return nil, storeError(err)
+	}
+	return &userpb.FindUserByEmailResponse{User: userToProto(u)}, nil
+}
+
+func (s *Server) GetUserStats(ctx context.Context, req *userpb.GetUserStatsRequest) (*userpb.GetUserStatsResponse, error) {
+	u, err := s.store.GetUser(ctx, req.GetUserId())
+	if err != nil {
+		return nil, storeError(err)
+	}
+	stats, err := s.store.GetStats(ctx, u.Id)
+	if err != nil {
+		return nil, err
+	}
+	unlocked, err := s.store.GetAchievements(ctx, u.Id)
+	if err != nil {
+		return nil, err
+	}
+	achievements := make([]*gamepb.Achievement, 0, len(unlocked))
+	for _, a := range unlocked {
+		// Achievements retired from the catalogue are not listed.
+		if info, ok := achievement.Lookup(a.Id); ok {
+			achievements = append(achievements, info.ToProto(a.GameId, a.Unlocked))
+		}
+	}
 
-	return &userpb.CreateUserResponse{
-		User: &userpb.User{
-			Id:    id,
-			Name:  req.GetName(),
-			Email: req.GetEmail(),
+	return &userpb.GetUserStatsResponse{
+		User: userToProto(u),
+		Stats: &userpb.UserStats{
+			GamesPlayed:       int32(stats.GamesPlayed),
+			Won:               int32(stats.Won),
+			Lost:              int32(stats.Lost),
+			Resigned:          int32(stats.Resigned),
+			Shots:             int32(stats.Shots),
+			Hits:              int32(stats.Hits),
+			Accuracy:          stats.Accuracy,
+			AverageShotsToWin: stats.AverageShotsToWin,
+			LongestWinStreak:  int32(stats.LongestWinStreak),
 		},
+		Achievements: achievements,
 	}, nil
 }
 
-func (s *Server) GetUsers(ctx context.Context, req *userpb.GetUsersRequest) (*userpb.GetUsersResponse, error) {
-	users, err := s.store.GetUsers()
+// Login checks the password and issues a session token. After MaxFailedLogins
+// wrong passwords in a row the account rejects logins for LockoutDuration.
+func (s *Server) Login(ctx context.Context, req *userpb.LoginRequest) (*userpb.LoginResponse, error) {
+	c, err := s.store.GetCredentials(ctx, "", normalizeEmail(req.GetEmail()))
+	if errors.Is(err, ErrNotFound) {
+		checkPassword("", req.GetPassword())
+		return nil, errInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if now.Before(c.LockedUntil) {
+		return nil, status.Errorf(codes.PermissionDenied, "account locked, try again in %s",
+			c.LockedUntil.Sub(now).Round(time.Second))
+	}
+	if !checkPassword(c.PasswordHash, req.GetPassword()) {
+		locked, err := s.store.RecordFailedLogin(ctx, c.Id, s.config.MaxFailedLogins, now.Add(s.config.LockoutDuration))
+		if err != nil {
+			return nil, err
+		}
+		if locked {
+			metrics.AccountsLocked.Inc()
+		}
+		return nil, errInvalidCredentials
+	}
 
+	if c.FailedLogins > 0 {
+		if err := s.store.ResetFailedLogins(ctx, c.Id); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.store.DeleteExpiredSessions(ctx, now); err != nil {
+		return nil, err
+	}
+	session, err := s.newSession(ctx, c.UserDto)
 	if err != nil {
 		return nil, err
 	}
+	return &userpb.LoginResponse{Session: session}, nil
+}
 
-	var pbUsers []*userpb.User
-	for _, u := range users {
-		pbUsers = append(pbUsers, &userpb.User{
-			Id:    u.Id,
-			Name:  u.Name,
-			Email: u.Email,
-		})
+func (s *Server) Logout(ctx context.Context, req *userpb.LogoutRequest) (*userpb.LogoutResponse, error) {
+	if err := s.store.DeleteSession(ctx, hashToken(auth.Token(ctx))); err != nil {
+		return nil, err
 	}
+	return &userpb.LogoutResponse{}, nil
+}
 
-	return &userpb.GetUsersResponse{
-		Users: pbUsers,
+// ChangePassword ends every session of the caller, including the current one,
+// and returns a fresh session.
+func (s *Server) ChangePassword(ctx context.Context, req *userpb.ChangePasswordRequest) (*userpb.ChangePasswordResponse, error) {
+	userId, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing session token")
+	}
+	if err := validatePassword(req.GetNewPassword()); err != nil {
+		return nil, err
+	}
+
+	c, err := s.store.GetCredentials(ctx, userId, "")
+	if err != nil {
+		return nil, storeError(err)
+	}
+	if !checkPassword(c.PasswordHash, req.GetOldPassword()) {
+		return nil, status.Error(codes.PermissionDenied, "wrong password")
+	}
+
+	passwordHash, err := HashPassword(req.GetNewPassword())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.SetPassword(ctx, userId, passwordHash); err != nil {
+		return nil, storeError(err)
+	}
+	if err := s.store.DeleteSessions(ctx, userId); err != nil {
+		return nil, err
+	}
+	session, err := s.newSession(ctx, c.UserDto)
+	if err != nil {
+		return nil, err
+	}
+	return &userpb.ChangePasswordResponse{Session: session}, nil
+}
+
+// Authenticate implements auth.Authenticator.
+func (s *Server) Authenticate(ctx context.Context, token string) (string, error) {
+	userId, expires, err := s.store.GetSession(ctx, hashToken(token))
+	if errors.Is(err, ErrSessionNotFound) || err == nil && time.Now().After(expires) {
+		return "", status.Error(codes.Unauthenticated, "invalid or expired session token")
+	}
+	if err != nil {
+		return "", err
+	}
+	return userId, nil
+}
+
+func (s *Server) newSession(ctx context.Context, u UserDto) (*userpb.Session, error) {
+	token, tokenHash, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	expires := time.Now().Add(s.config.SessionTTL)
+	if err := s.store.CreateSession(ctx, tokenHash, u.Id, expires); err != nil {
+		return nil, err
+	}
+	return &userpb.Session{
+		Token:     token,
+		ExpiresAt: timestamppb.New(expires),
+		User:      userToProto(u),
 	}, nil
 }
+
+func (s *Server) ListFriends(ctx context.Context, req *userpb.ListFriendsRequest) (*userpb.ListFriendsResponse, error) {
+	caller, _ := auth.UserID(ctx)
+	friends, err := s.store.GetFriends(ctx, caller)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &userpb.ListFriendsResponse{Friends: make([]*userpb.Friend, 0, len(friends))}
+	for _, f := range friends {
+		resp.Friends = append(resp.Friends, s.friendToProto(f))
+	}
+	return resp, nil
+}
+
+func (s *Server) RequestFriend(ctx context.Context, req *userpb.RequestFriendRequest) (*userpb.RequestFriendResponse, error) {
+	caller, _ := auth.UserID(ctx)
+	if req.GetUserId() == caller {
+		return nil, status.Error(codes.InvalidArgument, "you cannot befriend yourself")
+	}
+	u, err := s.store.GetUser(ctx, req.GetUserId())
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	friendStatus, err := s.store.RequestFriend(ctx, caller, u.Id)
+	if err != nil {
+		return nil, storeError(err)
+	}
+	if friendStatus == FriendAccepted {
+		s.presence.Befriend(caller, u.Id)
+	}
+	friend := FriendDto{User: u, Status: friendStatus, Since: time.Now()}
+	return &userpb.RequestFriendResponse{Friend: s.friendToProto(friend)}, nil
+}
+
+func (s *Server) AcceptFriend(ctx context.Context, req *userpb.AcceptFriendRequest) (*userpb.AcceptFriendResponse, error) {
+	caller, _ := auth.UserID(ctx)
+	u, err := s.store.GetUser(ctx, req.GetUserId())
+	if err != nil {
+		return nil, storeError(err)
+	}
+	if err := s.store.AcceptFriend(ctx, caller, u.Id); err != nil {
+		return nil, storeError(err)
+	}
+	s.presence.Befriend(caller, u.Id)
+
+	friend := FriendDto{User: u, Status: FriendAccepted, Since: time.Now()}
+	return &userpb.AcceptFriendResponse{Friend: s.friendToProto(friend)}, nil
+}
+
+func (s *Server) RemoveFriend(ctx context.Context, req *userpb.RemoveFriendRequest) (*userpb.RemoveFriendResponse, error) {
+	caller, _ := auth.UserID(ctx)
+	if err := s.store.RemoveFriend(ctx, caller, req.GetUserId()); err != nil {
+		return nil, storeError(err)
+	}
+	s.presence.Unfriend(caller, req.GetUserId())
+	return &userpb.RemoveFriendResponse{}, nil
+}
+
+// WatchPresence counts as an open stream itself, so a watching user is at
+// least IDLE to their friends.
+func (s *Server) WatchPresence(req *userpb.WatchPresenceRequest, stream userpb.UserService_WatchPresenceServer) error {
+	ctx := stream.Context()
+	caller, _ := auth.UserID(ctx)
+	friends, err := s.store.GetFriends(ctx, caller)
+	if err != nil {
+		return err
+	}
+	var friendIds []string
+	for _, f := range friends {
+		if f.Status == FriendAccepted {
+			friendIds = append(friendIds, f.User.Id)
+		}
+	}
+
+	s.presence.Connect(caller)
+	defer s.presence.Disconnect(caller)
+	if req.GetLookingForGame() {
+		s.presence.EnterQueue(caller)
+		defer s.presence.LeaveQueue(caller)
+	}
+	watcher := s.presence.Watch(caller, friendIds)
+	defer watcher.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.shutdown:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case <-watcher.Ready():
+			for _, u := range watcher.Updates() {
+				err := stream.Send(&userpb.PresenceUpdate{UserId: u.UserId, Status: presenceToProto(u.Status)})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// Shutdown ends the WatchPresence streams, which would otherwise keep the
+// server from stopping gracefully.
+func (s *Server) Shutdown() {
+	s.stopOnce.Do(func() { close(s.shutdown) })
+}
+
+func (s *Server) friendToProto(f FriendDto) *userpb.Friend {
+	friend := &userpb.Friend{
+		User:   userToProto(f.User),
+		Status: userpb.FriendStatus(userpb.FriendStatus_value[f.Status]),
+		Since:  timestamppb.New(f.Since),
+	}
+	if f.Status == FriendAccepted {
+		friend.Presence = presenceToProto(s.presence.Status(f.User.Id))
+	}
+	return friend
+}
+
+func presenceToProto(st presence.Status) userpb.PresenceStatus {
+	return userpb.PresenceStatus(userpb.PresenceStatus_value[string(st)])
+}
+
+// requireCaller only lets users change their own account.
+func requireCaller(ctx context.Context, id string) error {
+	if caller, _ := auth.UserID(ctx); caller != id {
+		return status.Error(codes.PermissionDenied, "you can only change your own account")
+	}
+	return nil
+}
+
+func validateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", status.Error(codes.InvalidArgument, "name must not be empty")
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return "", status.Errorf(codes.InvalidArgument, "name must be at most %d characters", maxNameLength)
+	}
+	return name, nil
+}
+
+// validateEmail accepts a bare address, such as "alice@example.com", and
+// returns it normalized.
+func validateEmail(email string) (string, error) {
+	email = normalizeEmail(email)
+	if email == "" {
+		return "", status.Error(codes.InvalidArgument, "email must not be empty")
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", status.Errorf(codes.InvalidArgument, "invalid email %q", email)
+	}
+	return email, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// storeError maps store errors to gRPC status codes.
+func storeError(err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrEmailTaken), errors.Is(err, ErrFriendExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, ErrFriendRequestNotFound), errors.Is(err, ErrFriendNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return err
+}
+
+// visibleUser leaves the email out unless the caller is the user, so that
+// users cannot be looked up to harvest addresses.
+func visibleUser(ctx context.Context, u UserDto) *userpb.User {
+	pb := userToProto(u)
+	if caller, _ := auth.UserID(ctx); caller != u.Id {
+		pb.Email = ""
+	}
+	return pb
+}
+
+func userToProto(u UserDto) *userpb.User {
+	return &userpb.User{
+		Id:    u.Id,
+		Name:  u.Name,
+		Email: u.Email,
+	}
+}
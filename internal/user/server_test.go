@@ -0,0 +1,208 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gosukretess/battleships/internal/auth"
+	"github.com/gosukretess/battleships/internal/database/dbtest"
+	"github.com/gosukretess/battleships/internal/presence"
+	"github.com/gosukretess/battleships/proto/userpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeGames stands in for the game server.
+type fakeGames struct {
+	resigned []string
+	err      error
+}
+
+func (g *fakeGames) ResignGames(ctx context.Context, userId string) (int, error) {
+	if g.err != nil {
+		return 0, g.err
+	}
+	g.resigned = append(g.resigned, userId)
+	return 1, nil
+}
+
+func newTestServer(t *testing.T, backend string, cfg Config) (*Server, *fakeGames) {
+	t.Helper()
+
+	games := &fakeGames{}
+	return NewServer(newTestStore(t, backend), presence.NewTracker(), games, cfg), games
+}
+
+// as returns a context authenticated as userId.
+func as(userId string) context.Context {
+	return auth.WithUserID(context.Background(), userId)
+}
+
+func TestDeleteUser(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		s, games := newTestServer(t, backend, Config{SessionTTL: time.Hour})
+
+		if _, err := s.DeleteUser(as("bob"), &userpb.DeleteUserRequest{Id: "alice"}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("deleting another user: error %v, want PermissionDenied", err)
+		}
+
+		// Nothing is deleted while the games cannot be ended.
+		games.err = errors.New("database is down")
+		if _, err := s.DeleteUser(as("alice"), &userpb.DeleteUserRequest{Id: "alice"}); err == nil {
+			t.Error("DeleteUser succeeded without ending the games")
+		}
+		if u, err := s.store.GetUser(context.Background(), "alice"); err != nil || u.Name != "Alice" {
+			t.Errorf("alice after a failed delete = %+v, %v", u, err)
+		}
+
+		games.err = nil
+		resp, err := s.DeleteUser(as("alice"), &userpb.DeleteUserRequest{Id: "alice"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.GetResignedGames() != 1 || len(games.resigned) != 1 || games.resigned[0] != "alice" {
+			t.Errorf("DeleteUser resigned %d games of %v, want the games of alice", resp.GetResignedGames(), games.resigned)
+		}
+		if u, _ := s.store.GetUser(context.Background(), "alice"); u.Name != DeletedUserName {
+			t.Errorf("alice after delete = %+v", u)
+		}
+	})
+}
+
+func TestLogin(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		s, _ := newTestServer(t, backend, Config{SessionTTL: time.Hour, MaxFailedLogins: 3, LockoutDuration: time.Hour})
+		ctx := context.Background()
+		for _, email := range []string{"carol@example.com", "dave@example.com"} {
+			if _, err := s.CreateUser(ctx, &userpb.CreateUserRequest{Name: "Player", Email: email, Password: "correct horse"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// The attempts run in order: three wrong passwords lock carol out,
+		// while dave's count starts over after he logs in.
+		attempts := []struct {
+			email, password string
+			want            codes.Code
+		}{
+			{email: "nobody@example.com", password: "correct horse", want: codes.Unauthenticated},
+			{email: "carol@example.com", password: "wrong", want: codes.Unauthenticated},
+			{email: "Carol@Example.com", password: "correct horse"},
+			{email: "carol@example.com", password: "wrong", want: codes.Unauthenticated},
+			{email: "carol@example.com", password: "wrong", want: codes.Unauthenticated},
+			{email: "carol@example.com", password: "wrong", want: codes.Unauthenticated},
+			{email: "carol@example.com", password: "correct horse", want: codes.PermissionDenied},
+			{email: "dave@example.com", password: "wrong", want: codes.Unauthenticated},
+			{email: "dave@example.com", password: "wrong", want: codes.Unauthenticated},
+			{email: "dave@example.com", password: "correct horse"},
+			{email: "dave@example.com", password: "wrong", want: codes.Unauthenticated},
+			{email: "dave@example.com", password: "wrong", want: codes.Unauthenticated},
+			{email: "dave@example.com", password: "correct horse"},
+		}
+		for i, a := range attempts {
+			resp, err := s.Login(ctx, &userpb.LoginRequest{Email: a.email, Password: a.password})
+			if status.Code(err) != a.want {
+				t.Errorf("attempt %d, %s with %q: error %v, want %v", i, a.email, a.password, err, a.want)
+				continue
+			}
+			if err == nil && (resp.GetSession().GetToken() == "" || !strings.EqualFold(resp.GetSession().GetUser().GetEmail(), a.email)) {
+				t.Errorf("attempt %d: session %+v", i, resp.GetSession())
+			}
+		}
+	})
+}
+
+func TestSessions(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		s, _ := newTestServer(t, backend, Config{SessionTTL: time.Hour})
+		ctx := context.Background()
+		created, err := s.CreateUser(ctx, &userpb.CreateUserRequest{Name: "Carol", Email: "carol@example.com", Password: "correct horse"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		login := func() string {
+			resp, err := s.Login(ctx, &userpb.LoginRequest{Email: "carol@example.com", Password: "correct horse"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return resp.GetSession().GetToken()
+		}
+		withToken := func(token string) context.Context {
+			md := metadata.Pairs(auth.Header, "Bearer "+token)
+			return auth.WithUserID(metadata.NewIncomingContext(ctx, md), created.GetUser().GetId())
+		}
+
+		kept, ended := login(), login()
+		if _, err := s.Logout(withToken(ended), &userpb.LogoutRequest{}); err != nil {
+			t.Fatal(err)
+		}
+		expired, _ := newTestServer(t, backend, Config{SessionTTL: -time.Second})
+		if _, err := expired.CreateUser(ctx, &userpb.CreateUserRequest{Name: "Carol", Email: "carol@example.com", Password: "correct horse"}); err != nil {
+			t.Fatal(err)
+		}
+		resp, err := expired.Login(ctx, &userpb.LoginRequest{Email: "carol@example.com", Password: "correct horse"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			name   string
+			server *Server
+			token  string
+			want   codes.Code
+		}{
+			{name: "valid", server: s, token: kept},
+			{name: "logged out", server: s, token: ended, want: codes.Unauthenticated},
+			{name: "unknown", server: s, token: "no-such-token", want: codes.Unauthenticated},
+			{name: "expired", server: expired, token: resp.GetSession().GetToken(), want: codes.Unauthenticated},
+		}
+		for _, tt := range tests {
+			userId, err := tt.server.Authenticate(ctx, tt.token)
+			if status.Code(err) != tt.want {
+				t.Errorf("%s session: error %v, want %v", tt.name, err, tt.want)
+				continue
+			}
+			if err == nil && userId != created.GetUser().GetId() {
+				t.Errorf("%s session belongs to %q, want %q", tt.name, userId, created.GetUser().GetId())
+			}
+		}
+
+		// A new password ends every other session.
+		if _, err := s.ChangePassword(withToken(kept), &userpb.ChangePasswordRequest{OldPassword: "correct horse", NewPassword: "battery staple"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Authenticate(ctx, kept); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("session after a password change: error %v, want Unauthenticated", err)
+		}
+	})
+}
+
+func TestGetUserStats(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		s, _ := newTestServer(t, backend, Config{SessionTTL: time.Hour})
+		for _, a := range []struct{ id, unlocked string }{{"FIRST_WIN", "2026-01-02T00:00:00Z"}, {"RETIRED", "2026-01-01T00:00:00Z"}} {
+			if _, err := s.store.db.Exec("INSERT INTO achievements (userid, achievement, gameid, unlocked) VALUES ('alice', ?, NULL, ?)", a.id, a.unlocked); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		resp, err := s.GetUserStats(context.Background(), &userpb.GetUserStatsRequest{UserId: "alice"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.GetUser().GetName() != "Alice" || resp.GetStats().GetGamesPlayed() != 0 {
+			t.Errorf("GetUserStats = %v", resp)
+		}
+		if len(resp.Achievements) != 1 || resp.Achievements[0].Id != "FIRST_WIN" {
+			t.Errorf("achievements = %v, want only FIRST_WIN", resp.Achievements)
+		}
+
+		if _, err := s.GetUserStats(context.Background(), &userpb.GetUserStatsRequest{UserId: "nobody"}); status.Code(err) != codes.NotFound {
+			t.Errorf("stats of an unknown user: error %v, want NotFound", err)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type StatsDto struct {
+	GamesPlayed       int
+	Won               int
+	Lost              int
+	Resigned          int
+	Shots             int
+	Hits              int
+	Accuracy          float64
+	AverageShotsToWin float64
+	LongestWinStreak  int
+}
+
+// GetStats computes the statistics of a user from their finished games and
+// all of their shots.
+func (s *Store) GetStats(ctx context.Context, userId string) (StatsDto, error) {
+	ctx, done := s.instrument(ctx, "get_stats")
+	defer done()
+
+	var stats StatsDto
+
+	// Games that ended before outcomes were recorded have no finish time and
+	// are ordered by when they started.
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT status, winner FROM games
+		WHERE (userid1 = ? OR userid2 = ?) AND status <> 'IN_PROGRESS'
+		ORDER BY COALESCE(finished, created)`, userId, userId)
+	if err != nil {
+		return stats, err
+	}
+	defer rows.Close()
+
+	streak := 0
+	for rows.Next() {
+		var status string
+		var winner sql.NullString
+		if err := rows.Scan(&status, &winner); err != nil {
+			return stats, err
+		}
+		stats.GamesPlayed++
+		if winner.String == userId {
+			stats.Won++
+			streak++
+			stats.LongestWinStreak = max(stats.LongestWinStreak, streak)
+			continue
+		}
+		stats.Lost++
+		streak = 0
+		if status == "RESIGNED" {
+			stats.Resigned++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return stats, err
+	}
+
+	err = s.db.QueryRowContext(ctx, "SELECT count(*), COALESCE(SUM(CASE WHEN hit THEN 1 ELSE 0 END), 0) FROM moves WHERE userid = ?", userId).
+		Scan(&stats.Shots, &stats.Hits)
+	if err != nil {
+		return stats, err
+	}
+	if stats.Shots > 0 {
+		stats.Accuracy = float64(stats.Hits) / float64(stats.Shots)
+	}
+
+	// A win by resignation says nothing about how many shots it takes.
+	var shotsInWins, sunkWins int
+	err = s.db.QueryRowContext(ctx, `
+		SELECT count(*), count(DISTINCT m.gameid) FROM moves m
+		JOIN games g ON g.id = m.gameid
+		WHERE m.userid = ? AND g.winner = ? AND g.status = 'FINISHED'`, userId, userId).
+		Scan(&shotsInWins, &sunkWins)
+	if err != nil {
+		return stats, err
+	}
+	if sunkWins > 0 {
+		stats.AverageShotsToWin = float64(shotsInWins) / float64(sunkWins)
+	}
+	return stats, nil
+}
+
+type AchievementDto struct {
+	Id       string
+	GameId   string
+	Unlocked time.Time
+}
+
+// GetAchievements lists the achievements a user has unlocked, oldest first.
+func (s *Store) GetAchievements(ctx context.Context, userId string) ([]AchievementDto, error) {
+	ctx, done := s.instrument(ctx, "get_achievements")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT achievement, gameid, unlocked FROM achievements WHERE userid = ? ORDER BY unlocked",
+		userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var achievements []AchievementDto
+	for rows.Next() {
+		var a AchievementDto
+		var gameId sql.NullString
+		var unlocked string
+		if err := rows.Scan(&a.Id, &gameId, &unlocked); err != nil {
+			return nil, err
+		}
+		a.GameId = gameId.String
+		a.Unlocked, _ = time.Parse(time.RFC3339, unlocked)
+		achievements = append(achievements, a)
+	}
+	return achievements, rows.Err()
+}
@@ -1,49 +1,87 @@
 package user
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"errors"
+	"time"
 
-	_ "modernc.org/sqlite"
+	"github.com/gosukretess/battleships/internal/database"
+	"github.com/gosukretess/battleships/internal/metrics"
+	"github.com/gosukretess/battleships/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var (
+	ErrNotFound        = errors.New("user not found")
+	ErrEmailTaken      = errors.New("email already in use")
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// DeletedUserName replaces the name of a deleted user, whose games stay in
+// the history of their opponents.
+const DeletedUserName = "Deleted user"
+
+// userColumns are scanned by scanUser. Deleted users have no email.
+const userColumns = "id, name, COALESCE(email, '')"
+
 type Store struct {
-	db *sql.DB
+	db *database.DB
 }
 
-func NewStore(path string) *Store {
-	db, err := sql.Open("sqlite", path)
-	if err != nil {
-		log.Fatal("Cannot open db", err)
-	}
+func NewStore(db *database.DB) *Store {
+	return &Store{db: db}
+}
 
-	createTable := `
-    CREATE TABLE IF NOT EXISTS users (
-        id TEXT PRIMARY KEY,
-        name TEXT,
-        email TEXT
-    );`
-	if _, err := db.Exec(createTable); err != nil {
-		log.Fatal("cannot create table:", err)
-	}
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
 
-	return &Store{db: db}
+// instrument starts a span and a latency measurement for a store operation.
+func (s *Store) instrument(ctx context.Context, operation string) (context.Context, func()) {
+	ctx, span := tracing.Start(ctx, "user.Store/"+operation, trace.WithAttributes(
+		attribute.String("db.system", s.db.Backend),
+		attribute.String("db.operation.name", operation),
+	))
+	observe := metrics.ObserveQuery("user", operation)
+	return ctx, func() {
+		observe()
+		span.End()
+	}
 }
 
-func (s *Store) CreateUser(id, name, email string) error {
-	_, err := s.db.Exec("INSERT INTO users(id, name, email) VALUES (?, ?, ?)", id, name, email)
+// CreateUser returns ErrEmailTaken if another user has the same email.
+func (s *Store) CreateUser(ctx context.Context, id, name, email, passwordHash string) error {
+	ctx, done := s.instrument(ctx, "create_user")
+	defer done()
+
+	_, err := s.db.ExecContext(ctx, "INSERT INTO users(id, name, email, password_hash) VALUES (?, ?, ?, ?)", id, name, email, passwordHash)
+	if database.IsUniqueViolation(err) {
+		return ErrEmailTaken
+	}
 	return err
 }
 
-func (s *Store) GetUser(id string) (string, string, error) {
-	row := s.db.QueryRow("SELECT name, email FROM users WHERE id = ?", id)
-	var name, email string
-	err := row.Scan(&name, &email)
-	return name, email, err
+func (s *Store) GetUser(ctx context.Context, id string) (UserDto, error) {
+	ctx, done := s.instrument(ctx, "get_user")
+	defer done()
+
+	return s.scanUser(s.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE id = ?", id))
+}
+
+func (s *Store) FindUserByEmail(ctx context.Context, email string) (UserDto, error) {
+	ctx, done := s.instrument(ctx, "find_user_by_email")
+	defer done()
+
+	return s.scanUser(s.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE email = ?", email))
 }
 
-func (s *Store) GetUsers() ([]UserDto, error) {
-	rows, err := s.db.Query("SELECT id, name, email FROM users")
+func (s *Store) GetUsers(ctx context.Context) ([]UserDto, error) {
+	ctx, done := s.instrument(ctx, "get_users")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+userColumns+" FROM users")
 	if err != nil {
 		return nil, err
 	}
@@ -60,8 +98,216 @@ func (s *Store) GetUsers() ([]UserDto, error) {
 	return users, nil
 }
 
+// UpdateUser overwrites name and email. It returns ErrNotFound for an unknown
+// id and ErrEmailTaken if another user has the same email.
+func (s *Store) UpdateUser(ctx context.Context, u UserDto) error {
+	ctx, done := s.instrument(ctx, "update_user")
+	defer done()
+
+	result, err := s.db.ExecContext(ctx, "UPDATE users SET name = ?, email = ? WHERE id = ?", u.Name, u.Email, u.Id)
+	if database.IsUniqueViolation(err) {
+		return ErrEmailTaken
+	}
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// DeleteUser removes the personal data of a user: their email, password,
+// sessions, friends, achievements and ratings. Their games stay in the
+// history of the opponents, under DeletedUserName. Games still in progress
+// have to be ended by the game server first.
+func (s *Store) DeleteUser(ctx context.Context, id string) error {
+	ctx, done := s.instrument(ctx, "delete_user")
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "UPDATE users SET name = ?, email = NULL, password_hash = NULL, failed_logins = 0, locked_until = NULL WHERE id = ?",
+		DeletedUserName, id)
+	if err != nil {
+		return err
+	}
+	if err := requireRow(result); err != nil {
+		return err
+	}
+
+	for _, table := range []string{"sessions", "achievements", "ratings", "rating_history"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE userid = ?", id); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM friends WHERE userid = ? OR friendid = ?", id, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetCredentials looks a user up by id or email together with their password
+// hash and lockout state.
+func (s *Store) GetCredentials(ctx context.Context, id, email string) (Credentials, error) {
+	ctx, done := s.instrument(ctx, "get_credentials")
+	defer done()
+
+	column, value := "id", id
+	if id == "" {
+		column, value = "email", email
+	}
+	query := "SELECT id, name, COALESCE(email, ''), password_hash, failed_logins, locked_until FROM users WHERE " + column + " = ?"
+
+	var c Credentials
+	var passwordHash, lockedUntil sql.NullString
+	err := s.db.QueryRowContext(ctx, query, value).
+		Scan(&c.Id, &c.Name, &c.Email, &passwordHash, &c.FailedLogins, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return c, ErrNotFound
+	}
+	if err != nil {
+		return c, err
+	}
+	c.PasswordHash = passwordHash.String
+	if lockedUntil.Valid {
+		c.LockedUntil, _ = time.Parse(time.RFC3339, lockedUntil.String)
+	}
+	return c, nil
+}
+
+// SetPassword replaces the password hash and lifts any lockout.
+func (s *Store) SetPassword(ctx context.Context, id, passwordHash string) error {
+	ctx, done := s.instrument(ctx, "set_password")
+	defer done()
+
+	result, err := s.db.ExecContext(ctx, "UPDATE users SET password_hash = ?, failed_logins = 0, locked_until = NULL WHERE id = ?", passwordHash, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// RecordFailedLogin counts a wrong password. Once maxFailed is reached the
+// account is locked until lockedUntil and the counter starts over.
+func (s *Store) RecordFailedLogin(ctx context.Context, id string, maxFailed int, lockedUntil time.Time) (bool, error) {
+	ctx, done := s.instrument(ctx, "record_failed_login")
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET failed_logins = failed_logins + 1 WHERE id = ?", id); err != nil {
+		return false, err
+	}
+	var failed int
+	if err := tx.QueryRowContext(ctx, "SELECT failed_logins FROM users WHERE id = ?", id).Scan(&failed); err != nil {
+		return false, err
+	}
+
+	locked := maxFailed > 0 && failed >= maxFailed
+	if locked {
+		_, err := tx.ExecContext(ctx, "UPDATE users SET failed_logins = 0, locked_until = ? WHERE id = ?", lockedUntil.UTC().Format(time.RFC3339), id)
+		if err != nil {
+			return false, err
+		}
+	}
+	return locked, tx.Commit()
+}
+
+func (s *Store) ResetFailedLogins(ctx context.Context, id string) error {
+	ctx, done := s.instrument(ctx, "reset_failed_logins")
+	defer done()
+
+	_, err := s.db.ExecContext(ctx, "UPDATE users SET failed_logins = 0, locked_until = NULL WHERE id = ?", id)
+	return err
+}
+
+func (s *Store) CreateSession(ctx context.Context, tokenHash, userId string, expires time.Time) error {
+	ctx, done := s.instrument(ctx, "create_session")
+	defer done()
+
+	_, err := s.db.ExecContext(ctx, "INSERT INTO sessions(token_hash, userid, created, expires) VALUES (?, ?, ?, ?)",
+		tokenHash, userId, time.Now().UTC().Format(time.RFC3339), expires.UTC().Format(time.RFC3339))
+	return err
+}
+
+// GetSession returns the user a session belongs to and when it expires.
+func (s *Store) GetSession(ctx context.Context, tokenHash string) (string, time.Time, error) {
+	ctx, done := s.instrument(ctx, "get_session")
+	defer done()
+
+	var userId, expires string
+	err := s.db.QueryRowContext(ctx, "SELECT userid, expires FROM sessions WHERE token_hash = ?", tokenHash).Scan(&userId, &expires)
+	if err == sql.ErrNoRows {
+		return "", time.Time{}, ErrSessionNotFound
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt, err := time.Parse(time.RFC3339, expires)
+	return userId, expiresAt, err
+}
+
+func (s *Store) DeleteSession(ctx context.Context, tokenHash string) error {
+	ctx, done := s.instrument(ctx, "delete_session")
+	defer done()
+
+	_, err := s.db.ExecContext(ctx, "DELETE FROM sessions WHERE token_hash = ?", tokenHash)
+	return err
+}
+
+// DeleteSessions ends every session of a user.
+func (s *Store) DeleteSessions(ctx context.Context, userId string) error {
+	ctx, done := s.instrument(ctx, "delete_sessions")
+	defer done()
+
+	_, err := s.db.ExecContext(ctx, "DELETE FROM sessions WHERE userid = ?", userId)
+	return err
+}
+
+func (s *Store) DeleteExpiredSessions(ctx context.Context, now time.Time) error {
+	ctx, done := s.instrument(ctx, "delete_expired_sessions")
+	defer done()
+
+	_, err := s.db.ExecContext(ctx, "DELETE FROM sessions WHERE expires < ?", now.UTC().Format(time.RFC3339))
+	return err
+}
+
+func (s *Store) scanUser(row *sql.Row) (UserDto, error) {
+	var u UserDto
+	err := row.Scan(&u.Id, &u.Name, &u.Email)
+	if err == sql.ErrNoRows {
+		return u, ErrNotFound
+	}
+	return u, err
+}
+
+func requireRow(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 type UserDto struct {
 	Id    string
 	Name  string
 	Email string
 }
+
+type Credentials struct {
+	UserDto
+	// PasswordHash is empty for users created before passwords existed.
+	PasswordHash string
+	FailedLogins int
+	LockedUntil  time.Time
+}
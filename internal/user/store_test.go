@@ -0,0 +1,333 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gosukretess/battleships/internal/database/dbtest"
+)
+
+func TestMain(m *testing.M) {
+	dbtest.Main(m)
+}
+
+func newTestStore(t *testing.T, backend string) *Store {
+	t.Helper()
+
+	store := NewStore(dbtest.New(t, backend))
+	for _, u := range []UserDto{
+		{Id: "alice", Name: "Alice", Email: "alice@example.com"},
+		{Id: "bob", Name: "Bob", Email: "bob@example.com"},
+	} {
+		if err := store.CreateUser(context.Background(), u.Id, u.Name, u.Email, "hash"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func TestStoreUsers(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		store := newTestStore(t, backend)
+
+		if err := store.CreateUser(ctx, "carol", "Carol", "alice@example.com", "hash"); !errors.Is(err, ErrEmailTaken) {
+			t.Errorf("duplicate email: error %v, want %v", err, ErrEmailTaken)
+		}
+
+		u, err := store.FindUserByEmail(ctx, "bob@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u != (UserDto{Id: "bob", Name: "Bob", Email: "bob@example.com"}) {
+			t.Errorf("FindUserByEmail = %+v", u)
+		}
+
+		if err := store.UpdateUser(ctx, UserDto{Id: "bob", Name: "Bobby", Email: "alice@example.com"}); !errors.Is(err, ErrEmailTaken) {
+			t.Errorf("update to a taken email: error %v, want %v", err, ErrEmailTaken)
+		}
+		if err := store.UpdateUser(ctx, UserDto{Id: "nobody", Name: "Nobody", Email: "nobody@example.com"}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("update of an unknown user: error %v, want %v", err, ErrNotFound)
+		}
+		if err := store.UpdateUser(ctx, UserDto{Id: "bob", Name: "Bobby", Email: "bobby@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+		if u, err := store.GetUser(ctx, "bob"); err != nil || u.Name != "Bobby" || u.Email != "bobby@example.com" {
+			t.Errorf("GetUser after update = %+v, %v", u, err)
+		}
+
+		users, err := store.GetUsers(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(users) != 2 {
+			t.Errorf("GetUsers returned %d users, want 2", len(users))
+		}
+	})
+}
+
+func TestStoreLockout(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		store := newTestStore(t, backend)
+		until := time.Now().Add(time.Minute).Truncate(time.Second)
+
+		for i, want := range []bool{false, false, true} {
+			locked, err := store.RecordFailedLogin(ctx, "alice", 3, until)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if locked != want {
+				t.Errorf("failed login %d: locked %v, want %v", i+1, locked, want)
+			}
+		}
+
+		c, err := store.GetCredentials(ctx, "", "alice@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.FailedLogins != 0 || !c.LockedUntil.Equal(until) {
+			t.Errorf("credentials after lockout: %d failed logins, locked until %v, want 0 and %v", c.FailedLogins, c.LockedUntil, until)
+		}
+
+		if err := store.SetPassword(ctx, "alice", "new hash"); err != nil {
+			t.Fatal(err)
+		}
+		c, err = store.GetCredentials(ctx, "alice", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.PasswordHash != "new hash" || !c.LockedUntil.IsZero() {
+			t.Errorf("credentials after a new password: hash %q, locked until %v", c.PasswordHash, c.LockedUntil)
+		}
+	})
+}
+
+func TestStoreSessions(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		store := newTestStore(t, backend)
+		now := time.Now().Truncate(time.Second)
+
+		if err := store.CreateSession(ctx, "current", "alice", now.Add(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.CreateSession(ctx, "expired", "alice", now.Add(-time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+
+		userId, expires, err := store.GetSession(ctx, "current")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if userId != "alice" || !expires.Equal(now.Add(time.Hour)) {
+			t.Errorf("GetSession = %s, %v", userId, expires)
+		}
+
+		if err := store.DeleteExpiredSessions(ctx, now); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := store.GetSession(ctx, "expired"); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("expired session: error %v, want %v", err, ErrSessionNotFound)
+		}
+
+		if err := store.DeleteSessions(ctx, "alice"); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := store.GetSession(ctx, "current"); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("session after logout everywhere: error %v, want %v", err, ErrSessionNotFound)
+		}
+	})
+}
+
+func TestStoreFriends(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		store := newTestStore(t, backend)
+
+		if state, err := store.RequestFriend(ctx, "alice", "bob"); err != nil || state != FriendOutgoing {
+			t.Fatalf("first request = %s, %v, want %s", state, err, FriendOutgoing)
+		}
+		if _, err := store.RequestFriend(ctx, "alice", "bob"); !errors.Is(err, ErrFriendExists) {
+			t.Errorf("repeated request: error %v, want %v", err, ErrFriendExists)
+		}
+
+		friends, err := store.GetFriends(ctx, "bob")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(friends) != 1 || friends[0].User.Id != "alice" || friends[0].Status != FriendIncoming {
+			t.Errorf("friends of bob = %+v, want an incoming request from alice", friends)
+		}
+
+		// Asking back accepts the request.
+		if state, err := store.RequestFriend(ctx, "bob", "alice"); err != nil || state != FriendAccepted {
+			t.Fatalf("request back = %s, %v, want %s", state, err, FriendAccepted)
+		}
+		if err := store.AcceptFriend(ctx, "bob", "alice"); !errors.Is(err, ErrFriendRequestNotFound) {
+			t.Errorf("accepting twice: error %v, want %v", err, ErrFriendRequestNotFound)
+		}
+
+		if err := store.RemoveFriend(ctx, "bob", "alice"); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.RemoveFriend(ctx, "bob", "alice"); !errors.Is(err, ErrFriendNotFound) {
+			t.Errorf("removing twice: error %v, want %v", err, ErrFriendNotFound)
+		}
+	})
+}
+
+func TestStoreDeleteUser(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		store := newTestStore(t, backend)
+		db := store.db
+
+		if _, err := db.Exec("INSERT INTO games(id, userid1, userid2, created, nextuser, status, winner) VALUES ('won', 'alice', 'bob', ?, 'alice', 'FINISHED', 'alice')",
+			time.Now().UTC().Format(time.RFC3339)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec("INSERT INTO moves(gameid, userid, x, y, hit, seq) VALUES ('won', 'alice', 1, 1, true, 1)"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.RequestFriend(ctx, "alice", "bob"); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.CreateSession(ctx, "token", "alice", time.Now().Add(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := store.DeleteUser(ctx, "alice"); err != nil {
+			t.Fatal(err)
+		}
+
+		u, err := store.GetUser(ctx, "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Name != DeletedUserName || u.Email != "" {
+			t.Errorf("deleted user = %+v, want the name %q and no email", u, DeletedUserName)
+		}
+		if _, err := store.FindUserByEmail(ctx, "alice@example.com"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("email of a deleted user: error %v, want %v", err, ErrNotFound)
+		}
+		if _, _, err := store.GetSession(ctx, "token"); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("session of a deleted user: error %v, want %v", err, ErrSessionNotFound)
+		}
+		if friends, _ := store.GetFriends(ctx, "bob"); len(friends) != 0 {
+			t.Errorf("bob still has friends %+v", friends)
+		}
+
+		stats, err := store.GetStats(ctx, "bob")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.GamesPlayed != 1 || stats.Lost != 1 {
+			t.Errorf("stats of bob = %+v, want the game lost against the deleted user", stats)
+		}
+
+		// The email can be used again.
+		if err := store.CreateUser(ctx, "carol", "Carol", "alice@example.com", "hash"); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.DeleteUser(ctx, "nobody"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("deleting an unknown user: error %v, want %v", err, ErrNotFound)
+		}
+	})
+}
+
+func TestStoreRequestFriendRace(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		store := newTestStore(t, backend)
+
+		for range 20 {
+			states := make(chan string, 2)
+			errs := make(chan error, 2)
+			for _, pair := range [][2]string{{"alice", "bob"}, {"bob", "alice"}} {
+				go func() {
+					state, err := store.RequestFriend(ctx, pair[0], pair[1])
+					states <- state
+					errs <- err
+				}()
+			}
+			got := map[string]int{}
+			for range 2 {
+				if err := <-errs; err != nil {
+					t.Fatal(err)
+				}
+				got[<-states]++
+			}
+			if got[FriendOutgoing] != 1 || got[FriendAccepted] != 1 {
+				t.Fatalf("requests at the same time ended as %v, want one %s and one %s", got, FriendOutgoing, FriendAccepted)
+			}
+
+			friends, err := store.GetFriends(ctx, "alice")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(friends) != 1 || friends[0].Status != FriendAccepted {
+				t.Fatalf("friends of alice = %+v, want bob once", friends)
+			}
+			if err := store.RemoveFriend(ctx, "alice", "bob"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestStoreGetStats(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		ctx := context.Background()
+		store := newTestStore(t, backend)
+		db := store.db
+		day := func(d int) string {
+			return time.Date(2026, time.January, d, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+		}
+
+		// Games of alice in the order they ended, with her shots.
+		games := []struct {
+			id, status string
+			winner     any
+			finished   any
+			shots      []bool
+		}{
+			{id: "g1", status: "FINISHED", winner: "alice", finished: day(2), shots: []bool{false, true, false, true}},
+			{id: "g2", status: "FINISHED", winner: "alice", finished: day(3), shots: []bool{true, true}},
+			{id: "g3", status: "RESIGNED", winner: "bob", finished: day(4), shots: []bool{false}},
+			{id: "g4", status: "RESIGNED", winner: "alice", finished: day(5)},
+			{id: "g5", status: "IN_PROGRESS", shots: []bool{true}},
+		}
+		for _, g := range games {
+			if _, err := db.Exec("INSERT INTO games(id, userid1, userid2, created, nextuser, status, winner, finished) VALUES (?, 'alice', 'bob', ?, 'alice', ?, ?, ?)",
+				g.id, day(1), g.status, g.winner, g.finished); err != nil {
+				t.Fatal(err)
+			}
+			for i, hit := range g.shots {
+				if _, err := db.Exec("INSERT INTO moves(gameid, userid, x, y, hit, seq) VALUES (?, 'alice', ?, 0, ?, ?)", g.id, i, hit, 2*i+1); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		tests := []struct {
+			userId string
+			want   StatsDto
+		}{
+			{userId: "alice", want: StatsDto{GamesPlayed: 4, Won: 3, Lost: 1, Resigned: 1, Shots: 8, Hits: 5, Accuracy: 5.0 / 8, AverageShotsToWin: 3, LongestWinStreak: 2}},
+			{userId: "bob", want: StatsDto{GamesPlayed: 4, Won: 1, Lost: 3, Resigned: 1, LongestWinStreak: 1}},
+			{userId: "nobody"},
+		}
+		for _, tt := range tests {
+			stats, err := store.GetStats(ctx, tt.userId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats != tt.want {
+				t.Errorf("stats of %s = %+v, want %+v", tt.userId, stats, tt.want)
+			}
+		}
+	})
+}
@@ -0,0 +1,742 @@
+"use strict";
+
+// Browser client for the REST gateway and the WebSocket bridge. It mirrors
+// the terminal client: log in, pick a game, fire by clicking the enemy board.
+
+const state = {
+  user: null,
+  token: null,
+  users: new Map(),
+  game: null,
+  opponent: null,
+  boardSize: 8,
+  ships: [],
+  moves: [],
+  enemyMoves: [],
+  yourTurn: false,
+  socket: null,
+  // tabs are the games in progress of the player, by id, all played over
+  // one socket.
+  tabs: new Map(),
+  friends: [],
+  presence: new Map(),
+  presenceWatch: null,
+};
+
+const $ = (id) => document.getElementById(id);
+
+// api calls the REST gateway with the session token. An expired session
+// sends the player back to the login form.
+async function api(path, options = {}) {
+  const headers = { ...options.headers };
+  if (state.token) {
+    headers.Authorization = `Bearer ${state.token}`;
+  }
+  if (options.body) {
+    headers["Content-Type"] = "application/json";
+  }
+  const response = await fetch(path, { ...options, headers });
+  const body = await response.json();
+  if (response.status === 401 && state.token) {
+    forgetSession();
+    showLogin();
+  }
+  if (!response.ok) {
+    throw new Error(body.message || response.statusText);
+  }
+  return body;
+}
+
+function post(path, body) {
+  return api(path, { method: "POST", body: JSON.stringify(body) });
+}
+
+function show(view) {
+  for (const id of ["login-view", "games-view", "game-view"]) {
+    $(id).hidden = id !== view;
+  }
+  $("session").hidden = state.user === null;
+  $("error").hidden = true;
+}
+
+function showError(err) {
+  $("error").textContent = err.message || String(err);
+  $("error").hidden = false;
+}
+
+function letter(n) {
+  return String.fromCharCode("A".charCodeAt(0) + n);
+}
+
+function coords(x, y) {
+  return `(${letter(x)},${y + 1})`;
+}
+
+function userName(id) {
+  const user = state.users.get(id);
+  return user ? user.name : id;
+}
+
+// Login
+
+function showLogin() {
+  closeSocket();
+  state.user = null;
+  show("login-view");
+  $("login-email").focus();
+}
+
+async function login(email, password) {
+  const { session } = await post("/v1/login", { email, password });
+  startSession(session);
+}
+
+async function register(name, email, password) {
+  await post("/v1/users", { name, email, password });
+  await login(email, password);
+}
+
+async function logout() {
+  try {
+    await post("/v1/logout", {});
+  } finally {
+    forgetSession();
+    showLogin();
+  }
+}
+
+function startSession(session) {
+  state.token = session.token;
+  state.user = session.user;
+  state.users.set(session.user.id, session.user);
+  sessionStorage.setItem("session", JSON.stringify(session));
+  $("current-user").textContent = state.user.name;
+  watchPresence();
+  showGames().catch(showError);
+}
+
+function forgetSession() {
+  stopWatchingPresence();
+  state.token = null;
+  state.user = null;
+  sessionStorage.removeItem("session");
+}
+
+function savedSession() {
+  try {
+    const session = JSON.parse(sessionStorage.getItem("session"));
+    if (session && session.token && new Date(session.expiresAt) > new Date()) {
+      return session;
+    }
+  } catch {
+    // Ignore whatever an older version left behind.
+  }
+  sessionStorage.removeItem("session");
+  return null;
+}
+
+// Game list
+
+async function showGames() {
+  closeSocket();
+  show("games-view");
+
+  const [{ users }, { stats, achievements }, { friends }] = await Promise.all([
+    api("/v1/users"),
+    api(`/v1/users/${encodeURIComponent(state.user.id)}/stats`),
+    api("/v1/friends"),
+  ]);
+  for (const user of users) {
+    state.users.set(user.id, user);
+  }
+  renderStats(stats);
+  renderAchievements(achievements || []);
+  setFriends(friends || []);
+
+  $("games").replaceChildren();
+  await loadGames("");
+}
+
+// loadGames appends a page of the games of the player, newest first.
+async function loadGames(pageToken) {
+  const params = new URLSearchParams({ userId: state.user.id, orderBy: "created desc", pageSize: 20 });
+  if ($("games-status").value) {
+    params.set("status", $("games-status").value);
+  }
+  if (pageToken) {
+    params.set("pageToken", pageToken);
+  }
+  const { games, nextPageToken } = await api(`/v1/games?${params}`);
+
+  const list = $("games");
+  const more = $("more-games");
+  more.hidden = !nextPageToken;
+  more.onclick = () => loadGames(nextPageToken).catch(showError);
+  if (games.length === 0 && !pageToken) {
+    list.innerHTML = "<li>Brak gier. Wyzwij znajomego, aby zacząć.</li>";
+    return;
+  }
+
+  for (const game of games) {
+    const opponent = game.userId1 === state.user.id ? game.userId2 : game.userId1;
+    const rules = game.rules || { name: "draft", boardSize: 8 };
+    const item = document.createElement("li");
+    item.innerHTML = `<div></div><div class="meta"></div>`;
+    item.children[0].textContent = `Przeciwnik: ${userName(opponent)}`;
+    item.children[1].textContent =
+      `${rules.name}, ${rules.boardSize}×${rules.boardSize} · utworzona ${new Date(game.created).toLocaleString()}` +
+      gameState(game);
+    item.addEventListener("click", () => openGame(game).catch(showError));
+    list.append(item);
+  }
+}
+
+function gameState(game) {
+  if (game.status === "IN_PROGRESS") {
+    const mode = game.mode === "CORRESPONDENCE" ? " · korespondencyjna" : "";
+    if (game.nextUser !== state.user.id) {
+      return mode;
+    }
+    const deadline = game.turnDeadline ? ` do ${new Date(game.turnDeadline).toLocaleString()}` : "";
+    return `${mode} · twój ruch${deadline}`;
+  }
+  const result = game.winner === state.user.id ? "wygrana" : "przegrana";
+  return game.status === "RESIGNED" ? ` · ${result} przez poddanie` : ` · ${result}`;
+}
+
+function renderStats(stats) {
+  const rows = [
+    ["Rozegrane gry", stats.gamesPlayed],
+    ["Wygrane", stats.won],
+    ["Przegrane", `${stats.lost} (w tym poddane: ${stats.resigned})`],
+    ["Celność", `${Math.round(stats.accuracy * 100)}% (${stats.hits}/${stats.shots})`],
+    ["Średnio strzałów do wygranej", stats.averageShotsToWin ? stats.averageShotsToWin.toFixed(1) : "–"],
+    ["Najdłuższa seria zwycięstw", stats.longestWinStreak],
+  ];
+  const list = $("stats");
+  list.replaceChildren();
+  for (const [label, value] of rows) {
+    const dt = document.createElement("dt");
+    dt.textContent = label;
+    const dd = document.createElement("dd");
+    dd.textContent = value;
+    list.append(dt, dd);
+  }
+}
+
+function renderAchievements(achievements) {
+  const list = $("achievements");
+  list.replaceChildren();
+  if (achievements.length === 0) {
+    list.innerHTML = "<li>Brak osiągnięć. Wygraj grę, aby zdobyć pierwsze.</li>";
+    return;
+  }
+  for (const achievement of achievements) {
+    const item = document.createElement("li");
+    item.innerHTML = `<div></div><div class="meta"></div>`;
+    item.children[0].textContent = achievement.name;
+    item.children[1].textContent =
+      `${achievement.description} · zdobyte ${new Date(achievement.unlocked).toLocaleString()}`;
+    list.append(item);
+  }
+}
+
+// Friends
+
+function setFriends(friends) {
+  state.friends = friends;
+  for (const friend of friends) {
+    state.users.set(friend.user.id, friend.user);
+    if (friend.presence && !state.presence.has(friend.user.id)) {
+      state.presence.set(friend.user.id, friend.presence);
+    }
+  }
+  renderFriends();
+}
+
+async function reloadFriends() {
+  const { friends } = await api("/v1/friends");
+  setFriends(friends || []);
+}
+
+const presenceNames = {
+  OFFLINE: "offline",
+  IDLE: "online",
+  IN_QUEUE: "szuka gry",
+  IN_GAME: "w grze",
+};
+
+function renderFriends() {
+  const list = $("friends");
+  list.replaceChildren();
+  if (state.friends.length === 0) {
+    list.innerHTML = "<li>Nie masz jeszcze znajomych. Zaproś kogoś po adresie email.</li>";
+    return;
+  }
+
+  for (const friend of state.friends) {
+    const item = document.createElement("li");
+    item.innerHTML = `<div><div></div><div class="meta"></div></div>`;
+    const [name, meta] = item.firstChild.children;
+    name.textContent = friend.user.name;
+    const id = friend.user.id;
+
+    if (friend.status === "FRIEND") {
+      const presence = state.presence.get(id) || "OFFLINE";
+      const dot = document.createElement("span");
+      dot.className = `presence ${presence}`;
+      name.prepend(dot);
+      meta.textContent = presenceNames[presence];
+      item.append(button("Wyzwij", () => challenge(id)));
+      item.append(button("Korespondencyjnie", () => challenge(id, "CORRESPONDENCE")));
+      item.append(button("Usuń", () => removeFriend(id, `Usunąć ${friend.user.name} ze znajomych?`)));
+    } else if (friend.status === "INCOMING") {
+      meta.textContent = "zaprasza Cię do znajomych";
+      item.append(button("Przyjmij", () => post(`/v1/friends/${encodeURIComponent(id)}:accept`, {}).then(reloadFriends)));
+      item.append(button("Odrzuć", () => removeFriend(id)));
+    } else {
+      meta.textContent = "zaproszenie wysłane";
+      item.append(button("Anuluj", () => removeFriend(id)));
+    }
+    list.append(item);
+  }
+}
+
+function button(text, onClick) {
+  const element = document.createElement("button");
+  element.type = "button";
+  element.textContent = text;
+  element.addEventListener("click", () => Promise.resolve(onClick()).catch(showError));
+  return element;
+}
+
+async function inviteFriend(email) {
+  const { user } = await api(`/v1/users:findByEmail?email=${encodeURIComponent(email)}`);
+  await post("/v1/friends", { userId: user.id });
+  await reloadFriends();
+}
+
+async function removeFriend(id, question) {
+  if (question && !confirm(question)) {
+    return;
+  }
+  await api(`/v1/friends/${encodeURIComponent(id)}`, { method: "DELETE" });
+  state.presence.delete(id);
+  await reloadFriends();
+}
+
+// challenge starts a game with a friend. Correspondence games can be played
+// over days, each player is notified when it is their turn.
+async function challenge(id, mode = "REALTIME") {
+  const { game } = await post("/v1/games", { userId1: state.user.id, userId2: id, mode });
+  await openGame(game);
+}
+
+// watchPresence follows the presence of friends over the streaming REST
+// endpoint, which sends one JSON object per line. Friends see the player as
+// waiting for a game while "Szukam gry" is ticked.
+async function watchPresence() {
+  stopWatchingPresence();
+  const controller = new AbortController();
+  state.presenceWatch = controller;
+  const lookingForGame = $("looking-for-game").checked;
+
+  try {
+    const response = await fetch(`/v1/presence?lookingForGame=${lookingForGame}`, {
+      headers: { Authorization: `Bearer ${state.token}` },
+      signal: controller.signal,
+    });
+    const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+    let buffered = "";
+    for (;;) {
+      const { value, done } = await reader.read();
+      if (done) {
+        break;
+      }
+      buffered += value;
+      const lines = buffered.split("\n");
+      buffered = lines.pop();
+      for (const line of lines) {
+        const { result } = JSON.parse(line);
+        if (result) {
+          state.presence.set(result.userId, result.status);
+        }
+      }
+      renderFriends();
+    }
+  } catch (err) {
+    if (err.name !== "AbortError") {
+      console.warn("presence stream ended", err);
+    }
+  }
+}
+
+function stopWatchingPresence() {
+  if (state.presenceWatch) {
+    state.presenceWatch.abort();
+    state.presenceWatch = null;
+  }
+}
+
+// Game
+
+// openGame shows a game. Opening a game in progress also subscribes to the
+// other games the player has in progress, so they can switch between them.
+async function openGame(game) {
+  if (game.status === "IN_PROGRESS") {
+    await loadTabs(game);
+    connect();
+  } else {
+    closeSocket();
+  }
+  await showGame(game);
+}
+
+async function loadTabs(selected) {
+  const params = new URLSearchParams({ userId: state.user.id, status: "IN_PROGRESS", orderBy: "created", pageSize: 100 });
+  const { games } = await api(`/v1/games?${params}`);
+  state.tabs.clear();
+  for (const game of [selected, ...games]) {
+    if (!state.tabs.has(game.id)) {
+      state.tabs.set(game.id, { game, yourTurn: game.nextUser === state.user.id, unread: false });
+    }
+  }
+}
+
+async function showGame(game) {
+  state.game = game;
+  state.opponent = game.userId1 === state.user.id ? game.userId2 : game.userId1;
+  state.boardSize = (game.rules && game.rules.boardSize) || 8;
+
+  const base = `/v1/games/${encodeURIComponent(game.id)}/users`;
+  const [ships, moves, enemyMoves] = await Promise.all([
+    api(`${base}/${encodeURIComponent(state.user.id)}/ships`),
+    api(`${base}/${encodeURIComponent(state.user.id)}/moves`),
+    api(`${base}/${encodeURIComponent(state.opponent)}/moves`),
+  ]);
+  state.ships = ships.ships;
+  state.moves = moves.moves;
+  state.enemyMoves = enemyMoves.moves;
+
+  $("log").replaceChildren();
+  show("game-view");
+  render();
+  const tab = state.tabs.get(game.id);
+  if (game.status !== "IN_PROGRESS" || !tab) {
+    renderTabs();
+    showResult(game.winner);
+    return;
+  }
+  tab.unread = false;
+  $("resign").hidden = false;
+  setTurn(tab.yourTurn);
+}
+
+// renderTabs shows a tab for each game in progress when there is more than
+// one. "●" marks games where it is the player's turn, "!" games where the
+// opponent moved since they were last shown.
+function renderTabs() {
+  const tabs = $("tabs");
+  tabs.replaceChildren();
+  tabs.hidden = state.tabs.size < 2;
+  for (const tab of state.tabs.values()) {
+    const opponent = tab.game.userId1 === state.user.id ? tab.game.userId2 : tab.game.userId1;
+    const button = document.createElement("button");
+    button.type = "button";
+    button.textContent = userName(opponent) + (tab.yourTurn ? " ●" : "") + (tab.unread ? " !" : "");
+    button.classList.toggle("current", state.game && tab.game.id === state.game.id);
+    button.classList.toggle("your-turn", tab.yourTurn);
+    button.addEventListener("click", () => showGame(tab.game).catch(showError));
+    tabs.append(button);
+  }
+}
+
+function showResult(winner) {
+  const won = winner === state.user.id;
+  setStatus(won ? "WYGRANA" : "PRZEGRANA", won ? "your-turn" : "stopped");
+  setTurn(false, false);
+  $("resign").hidden = true;
+}
+
+async function resign() {
+  if (!confirm("Na pewno chcesz się poddać?")) {
+    return;
+  }
+  await post(`/v1/games/${encodeURIComponent(state.game.id)}:resign`, {});
+}
+
+// connect opens the socket if needed and subscribes to every game in the
+// tabs. Subscribing to a game twice is harmless.
+function connect() {
+  if (state.socket) {
+    subscribeTabs(state.socket);
+    return;
+  }
+  const scheme = location.protocol === "https:" ? "wss" : "ws";
+  const url = `${scheme}://${location.host}/v1/ws?token=${encodeURIComponent(state.token)}`;
+  const socket = new WebSocket(url);
+  state.socket = socket;
+
+  socket.addEventListener("open", () => subscribeTabs(socket));
+  socket.addEventListener("message", (message) => handleEvent(JSON.parse(message.data)));
+  socket.addEventListener("close", (event) => {
+    if (state.socket !== socket) {
+      return;
+    }
+    state.socket = null;
+    if (event.code !== 1000) {
+      setStatus(`Połączenie zamknięte${event.reason ? ": " + event.reason : ""}`, "stopped");
+      setTurn(false, false);
+    }
+  });
+}
+
+function subscribeTabs(socket) {
+  if (socket.readyState !== WebSocket.OPEN) {
+    return;
+  }
+  for (const gameId of state.tabs.keys()) {
+    socket.send(JSON.stringify({ type: "SUBSCRIBE", gameId }));
+  }
+}
+
+function closeSocket() {
+  state.tabs.clear();
+  if (state.socket) {
+    const socket = state.socket;
+    state.socket = null;
+    socket.close(1000);
+  }
+}
+
+function handleEvent(event) {
+  if (event.type === "ACHIEVEMENT") {
+    log(`Nowe osiągnięcie: ${event.achievement.name} - ${event.achievement.description}`);
+    return;
+  }
+  if (event.type === "ERROR") {
+    handleError(event);
+    return;
+  }
+  if (event.gameId && event.gameId !== state.game.id) {
+    handleOtherGame(event);
+    return;
+  }
+
+  switch (event.type) {
+    case "SERVER_SHUTDOWN":
+      log("Serwer został wyłączony. Gra zostanie zapisana, odśwież stronę później.");
+      setStatus("SERWER WYŁĄCZONY", "stopped");
+      setTurn(false, false);
+      return;
+    case "GAME_OVER":
+      log(event.userId1 === state.user.id ? "Wygrałeś!" : "Przegrałeś.");
+      state.tabs.delete(event.gameId);
+      showResult(event.userId1);
+      if (state.tabs.size === 0) {
+        closeSocket();
+      }
+      renderTabs();
+      return;
+    case "TAKEN":
+      log(`Powtórzony strzał w ${coords(event.x, event.y)}. Wybierz inne pole.`);
+      setTurn(true);
+      return;
+    case "HIT":
+    case "MISS":
+      break;
+    default:
+      return;
+  }
+
+  const hit = event.type === "HIT";
+  const result = hit ? "TRAFIENIE" : "PUDŁO";
+  const move = { x: event.x, y: event.y, hit };
+  if (event.userId1 === state.user.id) {
+    state.moves.push(move);
+    log(`Strzeliłeś w ${coords(event.x, event.y)} - ${result}`);
+    setTurn(false);
+  } else {
+    state.enemyMoves.push(move);
+    log(`Przeciwnik strzelił w ${coords(event.x, event.y)} - ${result}`);
+    setTurn(true);
+  }
+  render();
+}
+
+const errorMessages = {
+  OUT_OF_BOUNDS: "Strzał poza planszą.",
+  NOT_YOUR_TURN: "Teraz ruch przeciwnika.",
+  GAME_NOT_FOUND: "Nie ma takiej gry.",
+  GAME_FINISHED: "Ta gra już się zakończyła.",
+  INTERNAL: "Błąd serwera. Spróbuj ponownie.",
+  NOT_A_PLAYER: "Nie grasz w tej grze.",
+  RATE_LIMITED: "Za dużo ruchów naraz. Zwolnij i spróbuj ponownie.",
+};
+
+// Errors after which it is still the player's turn.
+const retryableErrors = new Set(["OUT_OF_BOUNDS", "INTERNAL", "RATE_LIMITED"]);
+
+// handleError reports an event the server rejected. The shot was not
+// recorded, so after a shot outside the board, one sent too fast or a server
+// error it is still the player's turn.
+function handleError(event) {
+  log(errorMessages[event.errorCode] || event.errorMessage);
+  if (!retryableErrors.has(event.errorCode)) {
+    return;
+  }
+  if (state.game && event.gameId === state.game.id) {
+    setTurn(true);
+  } else if (state.tabs.has(event.gameId)) {
+    state.tabs.get(event.gameId).yourTurn = true;
+    renderTabs();
+  }
+}
+
+// handleOtherGame keeps the tab of a game that is not shown up to date.
+function handleOtherGame(event) {
+  const tab = state.tabs.get(event.gameId);
+  if (!tab) {
+    return;
+  }
+  const opponent = tab.game.userId1 === state.user.id ? tab.game.userId2 : tab.game.userId1;
+  switch (event.type) {
+    case "GAME_OVER":
+      log(event.userId1 === state.user.id ? `Wygrałeś z ${userName(opponent)}!` : `Przegrałeś z ${userName(opponent)}.`);
+      state.tabs.delete(event.gameId);
+      break;
+    case "HIT":
+    case "MISS":
+      tab.yourTurn = event.userId1 !== state.user.id;
+      tab.unread = tab.yourTurn;
+      if (tab.yourTurn) {
+        log(`${userName(opponent)} wykonał ruch - Twoja kolej w tej grze.`);
+      }
+      break;
+    default:
+      return;
+  }
+  renderTabs();
+}
+
+function fire(x, y) {
+  if (!state.yourTurn || !state.socket) {
+    return;
+  }
+  setTurn(false);
+  state.socket.send(JSON.stringify({ type: "MOVE", gameId: state.game.id, x, y }));
+}
+
+function setTurn(yourTurn, updateStatus = true) {
+  state.yourTurn = yourTurn;
+  const tab = state.game && state.tabs.get(state.game.id);
+  if (tab) {
+    tab.yourTurn = yourTurn;
+  }
+  renderTabs();
+  $("enemy-board").classList.toggle("active", yourTurn);
+  if (updateStatus) {
+    setStatus(yourTurn ? "TWOJA TURA" : "TURA PRZECIWNIKA", yourTurn ? "your-turn" : "enemy-turn");
+  }
+}
+
+function setStatus(text, className) {
+  $("status").textContent = text;
+  $("status").className = `status ${className}`;
+}
+
+function log(text) {
+  const item = document.createElement("li");
+  item.textContent = text;
+  $("log").append(item);
+  $("log").scrollTop = $("log").scrollHeight;
+}
+
+// Boards use the same symbols as the terminal client.
+
+function findAt(list, x, y) {
+  return list.find((item) => item.x === x && item.y === y);
+}
+
+function render() {
+  renderBoard($("user-board"), (x, y) => {
+    const move = findAt(state.enemyMoves, x, y);
+    if (move) {
+      return move.hit ? ["X", "hit"] : ["~", "enemy-miss"];
+    }
+    return findAt(state.ships, x, y) ? ["■", "ship"] : ["", "empty"];
+  });
+
+  renderBoard($("enemy-board"), (x, y) => {
+    const move = findAt(state.moves, x, y);
+    if (move) {
+      return move.hit ? ["X", "hit"] : ["o", "miss"];
+    }
+    return ["", "empty"];
+  }, fire);
+}
+
+function renderBoard(table, cellAt, onClick) {
+  const size = state.boardSize;
+  table.replaceChildren();
+
+  const header = table.insertRow();
+  header.append(document.createElement("th"));
+  for (let x = 0; x < size; x++) {
+    const th = document.createElement("th");
+    th.textContent = letter(x);
+    header.append(th);
+  }
+
+  for (let y = 0; y < size; y++) {
+    const row = table.insertRow();
+    const th = document.createElement("th");
+    th.textContent = y + 1;
+    row.append(th);
+    for (let x = 0; x < size; x++) {
+      const [symbol, className] = cellAt(x, y);
+      const cell = row.insertCell();
+      cell.className = `cell ${className}`;
+      cell.textContent = symbol;
+      if (onClick && className === "empty") {
+        cell.addEventListener("click", () => onClick(x, y));
+      }
+    }
+  }
+}
+
+function init() {
+  $("logout").addEventListener("click", () => logout().catch(showError));
+  $("back").addEventListener("click", () => showGames().catch(showError));
+  $("games-status").addEventListener("change", () => {
+    $("games").replaceChildren();
+    loadGames("").catch(showError);
+  });
+  $("resign").addEventListener("click", () => resign().catch(showError));
+
+  $("login-form").addEventListener("submit", (event) => {
+    event.preventDefault();
+    login($("login-email").value, $("login-password").value).catch(showError);
+    $("login-password").value = "";
+  });
+  $("friend-form").addEventListener("submit", (event) => {
+    event.preventDefault();
+    inviteFriend($("friend-email").value).catch(showError);
+    $("friend-email").value = "";
+  });
+  $("looking-for-game").addEventListener("change", () => watchPresence());
+  $("register-form").addEventListener("submit", (event) => {
+    event.preventDefault();
+    register($("register-name").value, $("register-email").value, $("register-password").value).catch(showError);
+    $("register-password").value = "";
+  });
+
+  const session = savedSession();
+  if (session) {
+    startSession(session);
+  } else {
+    showLogin();
+  }
+}
+
+init();
@@ -0,0 +1,193 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/gosukretess/battleships/internal/auth"
+	"github.com/gosukretess/battleships/internal/game"
+	"github.com/gosukretess/battleships/internal/logging"
+	"github.com/gosukretess/battleships/internal/ratelimit"
+	"github.com/gosukretess/battleships/proto/gamepb"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = pongWait * 9 / 10
+	maxMessage = 4096
+)
+
+// Messages use the protobuf JSON mapping of GameEvent, the same shape the
+// REST gateway produces, e.g. {"type": "MOVE", "x": 3, "y": 4}.
+var marshalOptions = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// Bridge lets browsers play over a WebSocket. A socket belongs to one player,
+// in one game or in every game it subscribes to, and shares the hub with the
+// gRPC streams, so browser and terminal players see each other's moves.
+// Sockets count against the rate limits of PlayerMove streams.
+type Bridge struct {
+	games    *game.Server
+	auth     auth.Authenticator
+	limiter  *ratelimit.Limiter
+	upgrader websocket.Upgrader
+}
+
+func NewBridge(games *game.Server, authenticator auth.Authenticator, limiter *ratelimit.Limiter) *Bridge {
+	return &Bridge{games: games, auth: authenticator, limiter: limiter}
+}
+
+// ServeHTTP handles GET /v1/games/{game_id}/ws?token=... and GET
+// /v1/ws?token=... Browsers cannot set headers on a WebSocket, so the session
+// token comes in the query. A socket for one game is closed with a policy
+// violation unless the user plays in it. A socket without a game sends
+// SUBSCRIBE and UNSUBSCRIBE events like a PlayerMove stream, and names the
+// game in every move.
+func (b *Bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	gameId := r.PathValue("game_id")
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, "token is required", http.StatusBadRequest)
+		return
+	}
+	userId, err := b.auth.Authenticate(r.Context(), token)
+	if err != nil {
+		http.Error(w, status.Convert(err).Message(), http.StatusUnauthorized)
+		return
+	}
+	if err := b.limiter.Check(gamepb.GameService_PlayerMove_FullMethodName, remoteIP(r), userId); err != nil {
+		http.Error(w, status.Convert(err).Message(), http.StatusTooManyRequests)
+		return
+	}
+
+	ws, err := b.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	requestID := r.Header.Get(logging.RequestIDHeader)
+	if !logging.ValidRequestID(requestID) {
+		requestID = uuid.New().String()
+	}
+	requestLogger := slog.Default().With("request_id", requestID, "method", "websocket", "caller_id", userId)
+	ctx := auth.WithUserID(logging.WithLogger(context.Background(), requestLogger), userId)
+	// HandleEvent answers events over the limit with an ERROR event.
+	ctx = b.limiter.WithEventLimit(ctx, gamepb.GameService_PlayerMove_FullMethodName, userId)
+	logger := requestLogger.With("user_id", userId)
+	if gameId != "" {
+		logger = logger.With("game_id", gameId)
+	}
+
+	conn, err := b.games.Connect(userId, func(event *gamepb.GameEvent) error {
+		data, err := marshalOptions.Marshal(event)
+		if err != nil {
+			return err
+		}
+		ws.SetWriteDeadline(time.Now().Add(writeWait))
+		return ws.WriteMessage(websocket.TextMessage, data)
+	})
+	if err != nil {
+		closeWith(ws, websocket.CloseTryAgainLater, status.Convert(err).Message())
+		return
+	}
+	defer b.games.Disconnect(conn)
+
+	if gameId != "" {
+		if err := b.games.Subscribe(ctx, conn, gameId, userId); err != nil {
+			logger.Warn("websocket rejected", "error", err)
+			closeWith(ws, websocket.ClosePolicyViolation, status.Convert(err).Message())
+			return
+		}
+	}
+	logger.Info("websocket connected")
+
+	events := make(chan *gamepb.GameEvent)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ws.SetReadLimit(maxMessage)
+		ws.SetReadDeadline(time.Now().Add(pongWait))
+		ws.SetPongHandler(func(string) error {
+			return ws.SetReadDeadline(time.Now().Add(pongWait))
+		})
+		for {
+			_, data, err := ws.ReadMessage()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			event := &gamepb.GameEvent{}
+			if err := protojson.Unmarshal(data, event); err != nil {
+				logger.Warn("invalid websocket message", "error", err)
+				continue
+			}
+			// The socket is bound to one player, and maybe one game, whatever
+			// the message says.
+			if gameId != "" {
+				event.GameId = gameId
+			}
+			event.UserId1 = userId
+			event.UserId2 = ""
+			select {
+			case events <- event:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(pingPeriod)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-b.games.ShuttingDown():
+			closeWith(ws, websocket.CloseGoingAway, "server is shutting down")
+			return
+		case err := <-readErr:
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.Warn("websocket closed", "error", err)
+			} else {
+				logger.Info("websocket closed")
+			}
+			return
+		case <-conn.Done():
+			err := conn.Err()
+			logger.Warn("websocket dropped", "error", err)
+			if errors.Is(err, game.ErrSlowConn) {
+				closeWith(ws, websocket.CloseTryAgainLater, "too many events pending")
+			}
+			return
+		case <-ping.C:
+			if err := ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		case event := <-events:
+			b.games.HandleEvent(ctx, conn, event)
+		}
+	}
+}
+
+func closeWith(ws *websocket.Conn, code int, reason string) {
+	ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(writeWait))
+}
+
+// remoteIP returns the address the request came from. Like the gateway it
+// does not trust X-Forwarded-For.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,196 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/gosukretess/battleships/internal/auth"
+	"github.com/gosukretess/battleships/internal/database/dbtest"
+	"github.com/gosukretess/battleships/internal/game"
+	"github.com/gosukretess/battleships/internal/presence"
+	"github.com/gosukretess/battleships/internal/ratelimit"
+	"github.com/gosukretess/battleships/internal/user"
+	"github.com/gosukretess/battleships/proto/gamepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestMain(m *testing.M) {
+	dbtest.Main(m)
+}
+
+// tokens authenticates the session token of a user, which is its id.
+type tokens struct{}
+
+func (tokens) Authenticate(ctx context.Context, token string) (string, error) {
+	if token != "alice" && token != "bob" && token != "carol" {
+		return "", status.Error(codes.Unauthenticated, "invalid session token")
+	}
+	return token, nil
+}
+
+// newTestBridge serves a bridge and returns its address and a game of alice
+// against bob.
+func newTestBridge(t *testing.T, backend string, limits ratelimit.Config) (string, *gamepb.Game) {
+	t.Helper()
+
+	db := dbtest.New(t, backend)
+	for _, id := range []string{"alice", "bob", "carol"} {
+		if _, err := db.Exec("INSERT INTO users(id, name, email) VALUES (?, ?, ?)", id, id, id+"@example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	games := game.NewServer(game.NewStore(db), game.NewHub(presence.NewTracker()), user.NewStore(db), nil, game.Config{})
+	resp, err := games.CreateGame(auth.WithUserID(context.Background(), "alice"), &gamepb.CreateGameRequest{UserId1: "alice", UserId2: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	bridge := NewBridge(games, tokens{}, ratelimit.New(limits))
+	mux.Handle("GET /v1/games/{game_id}/ws", bridge)
+	mux.Handle("GET /v1/ws", bridge)
+	server := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		games.Shutdown(ctx)
+		server.Close()
+	})
+	return "ws" + strings.TrimPrefix(server.URL, "http"), resp.Game
+}
+
+func dial(t *testing.T, addr, path, token string) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+
+	ws, resp, err := websocket.DefaultDialer.Dial(addr+path+"?token="+url.QueryEscape(token), nil)
+	if err == nil {
+		t.Cleanup(func() { ws.Close() })
+	}
+	return ws, resp, err
+}
+
+func send(t *testing.T, ws *websocket.Conn, message string) {
+	t.Helper()
+
+	if err := ws.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func receive(t *testing.T, ws *websocket.Conn) *gamepb.GameEvent {
+	t.Helper()
+
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := ws.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &gamepb.GameEvent{}
+	if err := protojson.Unmarshal(data, event); err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func TestBridgeHandshake(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		limits := ratelimit.Config{Default: ratelimit.MethodLimits{PerUser: ratelimit.Limit{Rate: 0.001, Burst: 2}}}
+		addr, g := newTestBridge(t, backend, limits)
+
+		tests := []struct {
+			name, path, token string
+			want              int
+		}{
+			{name: "no token", path: "/v1/ws", want: http.StatusBadRequest},
+			{name: "bad token", path: "/v1/ws", token: "mallory", want: http.StatusUnauthorized},
+			{name: "game", path: "/v1/games/" + g.Id + "/ws", token: "alice", want: http.StatusSwitchingProtocols},
+			{name: "all games", path: "/v1/ws", token: "alice", want: http.StatusSwitchingProtocols},
+			{name: "over the limit", path: "/v1/ws", token: "alice", want: http.StatusTooManyRequests},
+			{name: "other user", path: "/v1/ws", token: "bob", want: http.StatusSwitchingProtocols},
+		}
+		for _, tt := range tests {
+			_, resp, err := dial(t, addr, tt.path, tt.token)
+			if resp == nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("%s: status %d, want %d", tt.name, resp.StatusCode, tt.want)
+			}
+		}
+	})
+}
+
+func TestBridgeNotAPlayer(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		addr, g := newTestBridge(t, backend, ratelimit.Config{})
+
+		ws, _, err := dial(t, addr, "/v1/games/"+g.Id+"/ws", "carol")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, _, err = ws.ReadMessage()
+		var closeErr *websocket.CloseError
+		if !errors.As(err, &closeErr) || closeErr.Code != websocket.ClosePolicyViolation {
+			t.Errorf("socket of carol ended with %v, want close code %d", err, websocket.ClosePolicyViolation)
+		}
+	})
+}
+
+func TestBridgeMove(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		addr, g := newTestBridge(t, backend, ratelimit.Config{})
+
+		alice, _, err := dial(t, addr, "/v1/games/"+g.Id+"/ws", "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		bob, _, err := dial(t, addr, "/v1/ws", "bob")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Bob names the game in every event, and cannot speak for alice.
+		send(t, bob, `{"type": "SUBSCRIBE", "gameId": "`+g.Id+`"}`)
+		send(t, bob, `{"type": "MOVE", "gameId": "`+g.Id+`", "userId1": "alice", "x": 1, "y": 1}`)
+		if event := receive(t, bob); event.Type != gamepb.EventType_ERROR || event.ErrorCode != gamepb.ErrorCode_NOT_YOUR_TURN {
+			t.Errorf("bob firing out of turn received %v, want a NOT_YOUR_TURN error", event)
+		}
+
+		send(t, alice, `{"type": "MOVE", "x": 1, "y": 1}`)
+		shot := receive(t, alice)
+		if shot.Type != gamepb.EventType_HIT && shot.Type != gamepb.EventType_MISS {
+			t.Fatalf("alice received %v, want HIT or MISS", shot)
+		}
+		if event := receive(t, bob); event.Type != shot.Type || event.UserId1 != "alice" || event.X != 1 || event.Y != 1 {
+			t.Errorf("bob received %v, want the shot of alice", event)
+		}
+	})
+}
+
+func TestBridgeRateLimited(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, backend string) {
+		addr, g := newTestBridge(t, backend, ratelimit.Config{StreamEvents: ratelimit.Limit{Rate: 0.001, Burst: 1}})
+
+		ws, _, err := dial(t, addr, "/v1/games/"+g.Id+"/ws", "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		send(t, ws, `{"type": "MOVE", "x": 1, "y": 1}`)
+		if event := receive(t, ws); event.Type == gamepb.EventType_ERROR {
+			t.Fatalf("first shot rejected: %v", event)
+		}
+		send(t, ws, `{"type": "MOVE", "x": 2, "y": 2}`)
+		if event := receive(t, ws); event.Type != gamepb.EventType_ERROR || event.ErrorCode != gamepb.ErrorCode_RATE_LIMITED {
+			t.Errorf("shot over the limit answered with %v, want a RATE_LIMITED error", event)
+		}
+	})
+}
@@ -5,29 +5,42 @@ package internal
 
 import (
 	"github.com/google/wire"
+	"github.com/gosukretess/battleships/internal/database"
 	"github.com/gosukretess/battleships/internal/game"
+	"github.com/gosukretess/battleships/internal/healthcheck"
+	"github.com/gosukretess/battleships/internal/notify"
+	"github.com/gosukretess/battleships/internal/presence"
 	"github.com/gosukretess/battleships/internal/user"
 )
 
 type Server struct {
 	UserServer *user.Server
 	GameServer *game.Server
+	Health     *healthcheck.Checker
 }
 
-func NewServer(userServer *user.Server, gameServer *game.Server) *Server {
+func NewServer(userServer *user.Server, gameServer *game.Server, health *healthcheck.Checker) *Server {
 	return &Server{
 		UserServer: userServer,
 		GameServer: gameServer,
+		Health:     health,
 	}
 }
 
-func InitializeServers(dbPath string) (*Server, error) {
+func InitializeServers(dbConfig database.Config, userConfig user.Config, gameConfig game.Config, notifyConfig notify.Config) (*Server, func(), error) {
 	wire.Build(
+		database.NewDB,
+		presence.NewTracker,
 		user.NewStore,
+		wire.Bind(new(game.Players), new(*user.Store)),
 		user.NewServer,
+		wire.Bind(new(user.GameResigner), new(*game.Server)),
 		game.NewStore,
+		game.NewHub,
+		notify.New,
 		game.NewServer,
+		healthcheck.NewChecker,
 		NewServer,
 	)
-	return nil, nil
+	return nil, nil, nil
 }
@@ -7,19 +7,33 @@
 package internal
 
 import (
+	"github.com/gosukretess/battleships/internal/database"
 	"github.com/gosukretess/battleships/internal/game"
+	"github.com/gosukretess/battleships/internal/healthcheck"
+	"github.com/gosukretess/battleships/internal/notify"
+	"github.com/gosukretess/battleships/internal/presence"
 	"github.com/gosukretess/battleships/internal/user"
 )
 
 // Injectors from wire.go:
 
-func InitializeServers(dbPath string) (*Server, error) {
-	store := user.NewStore(dbPath)
-	server := user.NewServer(store)
-	gameStore := game.NewStore(dbPath)
-	gameServer := game.NewServer(gameStore)
-	internalServer := NewServer(server, gameServer)
-	return internalServer, nil
+func InitializeServers(dbConfig database.Config, userConfig user.Config, gameConfig game.Config, notifyConfig notify.Config) (*Server, func(), error) {
+	db, cleanup, err := database.NewDB(dbConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	store := user.NewStore(db)
+	tracker := presence.NewTracker()
+	gameStore := game.NewStore(db)
+	hub := game.NewHub(tracker)
+	notifier := notify.New(notifyConfig)
+	server := game.NewServer(gameStore, hub, store, notifier, gameConfig)
+	userServer := user.NewServer(store, tracker, server, userConfig)
+	checker := healthcheck.NewChecker(store, gameStore)
+	internalServer := NewServer(userServer, server, checker)
+	return internalServer, func() {
+		cleanup()
+	}, nil
 }
 
 // wire.go:
@@ -27,11 +41,13 @@ func InitializeServers(dbPath string) (*Server, error) {
 type Server struct {
 	UserServer *user.Server
 	GameServer *game.Server
+	Health     *healthcheck.Checker
 }
 
-func NewServer(userServer *user.Server, gameServer *game.Server) *Server {
+func NewServer(userServer *user.Server, gameServer *game.Server, health *healthcheck.Checker) *Server {
 	return &Server{
 		UserServer: userServer,
 		GameServer: gameServer,
+		Health:     health,
 	}
 }
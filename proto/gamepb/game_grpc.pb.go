@@ -19,11 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	GameService_CreateGame_FullMethodName  = "/game.GameService/CreateGame"
-	GameService_GetAllGames_FullMethodName = "/game.GameService/GetAllGames"
-	GameService_PlayerMove_FullMethodName  = "/game.GameService/PlayerMove"
-	GameService_GetShips_FullMethodName    = "/game.GameService/GetShips"
-	GameService_GetMoves_FullMethodName    = "/game.GameService/GetMoves"
+	GameService_CreateGame_FullMethodName       = "/game.GameService/CreateGame"
+	GameService_GetAllGames_FullMethodName      = "/game.GameService/GetAllGames"
+	GameService_ListGames_FullMethodName        = "/game.GameService/ListGames"
+	GameService_GetGame_FullMethodName          = "/game.GameService/GetGame"
+	GameService_PlayerMove_FullMethodName       = "/game.GameService/PlayerMove"
+	GameService_GetShips_FullMethodName         = "/game.GameService/GetShips"
+	GameService_GetMoves_FullMethodName         = "/game.GameService/GetMoves"
+	GameService_FireShot_FullMethodName         = "/game.GameService/FireShot"
+	GameService_GetInbox_FullMethodName         = "/game.GameService/GetInbox"
+	GameService_ResignGame_FullMethodName       = "/game.GameService/ResignGame"
+	GameService_GetLeaderboard_FullMethodName   = "/game.GameService/GetLeaderboard"
+	GameService_GetRatingHistory_FullMethodName = "/game.GameService/GetRatingHistory"
 )
 
 // GameServiceClient is the client API for GameService service.
@@ -31,10 +38,27 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type GameServiceClient interface {
 	CreateGame(ctx context.Context, in *CreateGameRequest, opts ...grpc.CallOption) (*CreateGameResponse, error)
+	// GetAllGames returns every game at once. Use ListGames instead.
 	GetAllGames(ctx context.Context, in *GetAllGamesRequest, opts ...grpc.CallOption) (*GetAllGamesResponse, error)
+	// Games are public: any logged in user can list and look them up, but
+	// only the players see their ships and moves.
+	ListGames(ctx context.Context, in *ListGamesRequest, opts ...grpc.CallOption) (*ListGamesResponse, error)
+	GetGame(ctx context.Context, in *GetGameRequest, opts ...grpc.CallOption) (*GetGameResponse, error)
 	PlayerMove(ctx context.Context, opts ...grpc.CallOption) (GameService_PlayerMoveClient, error)
 	GetShips(ctx context.Context, in *GetShipsRequest, opts ...grpc.CallOption) (*GetShipsResponse, error)
+	// GetMoves lists the shots of either player of a game. Only its players
+	// can see them.
 	GetMoves(ctx context.Context, in *GetMovesRequest, opts ...grpc.CallOption) (*GetMovesResponse, error)
+	// FireShot fires one shot without a PlayerMove stream, e.g. in a
+	// correspondence game. The result is also sent to the streams of both
+	// players.
+	FireShot(ctx context.Context, in *FireShotRequest, opts ...grpc.CallOption) (*FireShotResponse, error)
+	// GetInbox lists the games in progress where it is the caller's turn.
+	GetInbox(ctx context.Context, in *GetInboxRequest, opts ...grpc.CallOption) (*GetInboxResponse, error)
+	// ResignGame ends the game as lost for the caller.
+	ResignGame(ctx context.Context, in *ResignGameRequest, opts ...grpc.CallOption) (*ResignGameResponse, error)
+	GetLeaderboard(ctx context.Context, in *GetLeaderboardRequest, opts ...grpc.CallOption) (*GetLeaderboardResponse, error)
+	GetRatingHistory(ctx context.Context, in *GetRatingHistoryRequest, opts ...grpc.CallOption) (*GetRatingHistoryResponse, error)
 }
 
 type gameServiceClient struct {
@@ -63,6 +87,24 @@ func (c *gameServiceClient) GetAllGames(ctx context.Context, in *GetAllGamesRequ
 	return out, nil
 }
 
+func (c *gameServiceClient) ListGames(ctx context.Context, in *ListGamesRequest, opts ...grpc.CallOption) (*ListGamesResponse, error) {
+	out := new(ListGamesResponse)
+	err := c.cc.Invoke(ctx, GameService_ListGames_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameServiceClient) GetGame(ctx context.Context, in *GetGameRequest, opts ...grpc.CallOption) (*GetGameResponse, error) {
+	out := new(GetGameResponse)
+	err := c.cc.Invoke(ctx, GameService_GetGame_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gameServiceClient) PlayerMove(ctx context.Context, opts ...grpc.CallOption) (GameService_PlayerMoveClient, error) {
 	stream, err := c.cc.NewStream(ctx, &GameService_ServiceDesc.Streams[0], GameService_PlayerMove_FullMethodName, opts...)
 	if err != nil {
@@ -112,15 +154,77 @@ func (c *gameServiceClient) GetMoves(ctx context.Context, in *GetMovesRequest, o
 	return out, nil
 }
 
+func (c *gameServiceClient) FireShot(ctx context.Context, in *FireShotRequest, opts ...grpc.CallOption) (*FireShotResponse, error) {
+	out := new(FireShotResponse)
+	err := c.cc.Invoke(ctx, GameService_FireShot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameServiceClient) GetInbox(ctx context.Context, in *GetInboxRequest, opts ...grpc.CallOption) (*GetInboxResponse, error) {
+	out := new(GetInboxResponse)
+	err := c.cc.Invoke(ctx, GameService_GetInbox_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameServiceClient) ResignGame(ctx context.Context, in *ResignGameRequest, opts ...grpc.CallOption) (*ResignGameResponse, error) {
+	out := new(ResignGameResponse)
+	err := c.cc.Invoke(ctx, GameService_ResignGame_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameServiceClient) GetLeaderboard(ctx context.Context, in *GetLeaderboardRequest, opts ...grpc.CallOption) (*GetLeaderboardResponse, error) {
+	out := new(GetLeaderboardResponse)
+	err := c.cc.Invoke(ctx, GameService_GetLeaderboard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameServiceClient) GetRatingHistory(ctx context.Context, in *GetRatingHistoryRequest, opts ...grpc.CallOption) (*GetRatingHistoryResponse, error) {
+	out := new(GetRatingHistoryResponse)
+	err := c.cc.Invoke(ctx, GameService_GetRatingHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GameServiceServer is the server API for GameService service.
 // All implementations must embed UnimplementedGameServiceServer
 // for forward compatibility
 type GameServiceServer interface {
 	CreateGame(context.Context, *CreateGameRequest) (*CreateGameResponse, error)
+	// GetAllGames returns every game at once. Use ListGames instead.
 	GetAllGames(context.Context, *GetAllGamesRequest) (*GetAllGamesResponse, error)
+	// Games are public: any logged in user can list and look them up, but
+	// only the players see their ships and moves.
+	ListGames(context.Context, *ListGamesRequest) (*ListGamesResponse, error)
+	GetGame(context.Context, *GetGameRequest) (*GetGameResponse, error)
 	PlayerMove(GameService_PlayerMoveServer) error
 	GetShips(context.Context, *GetShipsRequest) (*GetShipsResponse, error)
+	// GetMoves lists the shots of either player of a game. Only its players
+	// can see them.
 	GetMoves(context.Context, *GetMovesRequest) (*GetMovesResponse, error)
+	// FireShot fires one shot without a PlayerMove stream, e.g. in a
+	// correspondence game. The result is also sent to the streams of both
+	// players.
+	FireShot(context.Context, *FireShotRequest) (*FireShotResponse, error)
+	// GetInbox lists the games in progress where it is the caller's turn.
+	GetInbox(context.Context, *GetInboxRequest) (*GetInboxResponse, error)
+	// ResignGame ends the game as lost for the caller.
+	ResignGame(context.Context, *ResignGameRequest) (*ResignGameResponse, error)
+	GetLeaderboard(context.Context, *GetLeaderboardRequest) (*GetLeaderboardResponse, error)
+	GetRatingHistory(context.Context, *GetRatingHistoryRequest) (*GetRatingHistoryResponse, error)
 	mustEmbedUnimplementedGameServiceServer()
 }
 
@@ -134,6 +238,12 @@ func (UnimplementedGameServiceServer) CreateGame(context.Context, *CreateGameReq
 func (UnimplementedGameServiceServer) GetAllGames(context.Context, *GetAllGamesRequest) (*GetAllGamesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllGames not implemented")
 }
+func (UnimplementedGameServiceServer) ListGames(context.Context, *ListGamesRequest) (*ListGamesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGames not implemented")
+}
+func (UnimplementedGameServiceServer) GetGame(context.Context, *GetGameRequest) (*GetGameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGame not implemented")
+}
 func (UnimplementedGameServiceServer) PlayerMove(GameService_PlayerMoveServer) error {
 	return status.Errorf(codes.Unimplemented, "method PlayerMove not implemented")
 }
@@ -143,6 +253,21 @@ func (UnimplementedGameServiceServer) GetShips(context.Context, *GetShipsRequest
 func (UnimplementedGameServiceServer) GetMoves(context.Context, *GetMovesRequest) (*GetMovesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMoves not implemented")
 }
+func (UnimplementedGameServiceServer) FireShot(context.Context, *FireShotRequest) (*FireShotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FireShot not implemented")
+}
+func (UnimplementedGameServiceServer) GetInbox(context.Context, *GetInboxRequest) (*GetInboxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInbox not implemented")
+}
+func (UnimplementedGameServiceServer) ResignGame(context.Context, *ResignGameRequest) (*ResignGameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResignGame not implemented")
+}
+func (UnimplementedGameServiceServer) GetLeaderboard(context.Context, *GetLeaderboardRequest) (*GetLeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLeaderboard not implemented")
+}
+func (UnimplementedGameServiceServer) GetRatingHistory(context.Context, *GetRatingHistoryRequest) (*GetRatingHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRatingHistory not implemented")
+}
 func (UnimplementedGameServiceServer) mustEmbedUnimplementedGameServiceServer() {}
 
 // UnsafeGameServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -192,6 +317,42 @@ func _GameService_GetAllGames_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GameService_ListGames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGamesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServiceServer).ListGames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GameService_ListGames_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServiceServer).ListGames(ctx, req.(*ListGamesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GameService_GetGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServiceServer).GetGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GameService_GetGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServiceServer).GetGame(ctx, req.(*GetGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GameService_PlayerMove_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(GameServiceServer).PlayerMove(&gameServicePlayerMoveServer{stream})
 }
@@ -254,6 +415,96 @@ func _GameService_GetMoves_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GameService_FireShot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FireShotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServiceServer).FireShot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GameService_FireShot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServiceServer).FireShot(ctx, req.(*FireShotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GameService_GetInbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServiceServer).GetInbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GameService_GetInbox_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServiceServer).GetInbox(ctx, req.(*GetInboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GameService_ResignGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResignGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServiceServer).ResignGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GameService_ResignGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServiceServer).ResignGame(ctx, req.(*ResignGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GameService_GetLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServiceServer).GetLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GameService_GetLeaderboard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServiceServer).GetLeaderboard(ctx, req.(*GetLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GameService_GetRatingHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRatingHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServiceServer).GetRatingHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GameService_GetRatingHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServiceServer).GetRatingHistory(ctx, req.(*GetRatingHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // GameService_ServiceDesc is the grpc.ServiceDesc for GameService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -269,6 +520,14 @@ var GameService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAllGames",
 			Handler:    _GameService_GetAllGames_Handler,
 		},
+		{
+			MethodName: "ListGames",
+			Handler:    _GameService_ListGames_Handler,
+		},
+		{
+			MethodName: "GetGame",
+			Handler:    _GameService_GetGame_Handler,
+		},
 		{
 			MethodName: "GetShips",
 			Handler:    _GameService_GetShips_Handler,
@@ -277,6 +536,26 @@ var GameService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMoves",
 			Handler:    _GameService_GetMoves_Handler,
 		},
+		{
+			MethodName: "FireShot",
+			Handler:    _GameService_FireShot_Handler,
+		},
+		{
+			MethodName: "GetInbox",
+			Handler:    _GameService_GetInbox_Handler,
+		},
+		{
+			MethodName: "ResignGame",
+			Handler:    _GameService_ResignGame_Handler,
+		},
+		{
+			MethodName: "GetLeaderboard",
+			Handler:    _GameService_GetLeaderboard_Handler,
+		},
+		{
+			MethodName: "GetRatingHistory",
+			Handler:    _GameService_GetRatingHistory_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{